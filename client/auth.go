@@ -0,0 +1,190 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/utils"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Authenticator adds credentials to outgoing requests.
+// Implementations must not expose secrets through String or GoString, since clients may be logged.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc adapts a plain function to the Authenticator interface.
+type AuthenticatorFunc func(req *http.Request) error
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// String implements fmt.Stringer without revealing anything the function may capture.
+func (f AuthenticatorFunc) String() string {
+	return "AuthenticatorFunc"
+}
+
+// BearerToken sends a static token in the Authorization header.
+type BearerToken string
+
+// Authenticate sets the Authorization header.
+func (t BearerToken) Authenticate(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// String implements fmt.Stringer with the token redacted.
+func (t BearerToken) String() string {
+	return "BearerToken(" + redact(string(t)) + ")"
+}
+
+// GoString keeps the token out of %#v output.
+func (t BearerToken) GoString() string {
+	return t.String()
+}
+
+// BasicAuth sends HTTP basic authentication credentials.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// Authenticate sets the Authorization header.
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+	return nil
+}
+
+// String implements fmt.Stringer with the password redacted.
+func (a BasicAuth) String() string {
+	return "BasicAuth(" + a.Username + ", " + redact(a.Password) + ")"
+}
+
+// GoString keeps the password out of %#v output.
+func (a BasicAuth) GoString() string {
+	return a.String()
+}
+
+// HeaderAuth sets arbitrary headers on every request (e.g., API gateway keys).
+type HeaderAuth map[string]string
+
+// Authenticate sets each configured header.
+func (h HeaderAuth) Authenticate(req *http.Request) error {
+	for name, value := range h {
+		req.Header.Set(name, value)
+	}
+	return nil
+}
+
+// String implements fmt.Stringer listing header names only.
+func (h HeaderAuth) String() string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	return fmt.Sprintf("HeaderAuth(%v)", names)
+}
+
+// GoString keeps header values out of %#v output.
+func (h HeaderAuth) GoString() string {
+	return h.String()
+}
+
+// MultiAuth applies several authenticators in order.
+type MultiAuth []Authenticator
+
+// Authenticate applies every authenticator, stopping at the first error.
+func (m MultiAuth) Authenticate(req *http.Request) error {
+	for _, auth := range m {
+		if err := auth.Authenticate(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Invalidate drops cached credentials in every member that caches them (e.g., a TokenProvider).
+// The client calls it when the server rejects a request with 401 Unauthorized.
+func (m MultiAuth) Invalidate() {
+	for _, auth := range m {
+		if invalidator, ok := auth.(interface{ Invalidate() }); ok {
+			invalidator.Invalidate()
+		}
+	}
+}
+
+// TokenFetcher obtains a fresh bearer token and the time it expires.
+// A zero expiry means the token does not expire.
+type TokenFetcher func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// TokenProvider sends rotating bearer tokens, caching each one until shortly before it expires.
+type TokenProvider struct {
+	fetch  TokenFetcher
+	leeway time.Duration
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewTokenProvider creates a TokenProvider that refreshes tokens leeway before they expire.
+func NewTokenProvider(fetch TokenFetcher, leeway time.Duration) *TokenProvider {
+	return &TokenProvider{fetch: fetch, leeway: leeway}
+}
+
+// Authenticate sets the Authorization header, fetching a new token if the cached one is stale.
+func (p *TokenProvider) Authenticate(req *http.Request) error {
+	token, err := p.Token(req.Context())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Token returns the cached token, fetching a new one when it is missing or about to expire.
+func (p *TokenProvider) Token(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && (p.expiry.IsZero() || time.Now().Add(p.leeway).Before(p.expiry)) {
+		return p.token, nil
+	}
+
+	token, expiry, err := p.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", utils.ErrAuthentication, err)
+	}
+	p.token, p.expiry = token, expiry
+	return token, nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one.
+// The client calls it when the server rejects a request with 401 Unauthorized.
+func (p *TokenProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.token = ""
+}
+
+// String implements fmt.Stringer without revealing the cached token.
+func (p *TokenProvider) String() string {
+	return "TokenProvider"
+}
+
+// GoString keeps the cached token out of %#v output.
+func (p *TokenProvider) GoString() string {
+	return p.String()
+}
+
+// redact hides a secret while hinting whether one is set.
+func redact(secret string) string {
+	if secret == "" {
+		return `""`
+	}
+	return "[REDACTED]"
+}
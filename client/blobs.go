@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+)
+
+// digestPattern matches the blob digests accepted by the server.
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// FileDigest computes the sha256 digest of the file at path, in the "sha256:<hex>" form used by the blob API.
+// The file is streamed, so it may be larger than memory.
+func FileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return readerDigest(f)
+}
+
+// readerDigest computes the sha256 digest of everything read from r.
+func readerDigest(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// BlobExists reports whether the server already has the blob with the given digest.
+func (c *OllamaClient) BlobExists(ctx context.Context, digest string) (bool, error) {
+	if !digestPattern.MatchString(digest) {
+		return false, fmt.Errorf("invalid blob digest %q", digest)
+	}
+	err := c.exec(ctx, "HEAD", "/api/blobs/"+digest, nil)
+	var apiErr *utils.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// CreateBlob uploads size bytes read from body as the blob with the given digest.
+// The body is streamed; it is rewound to the start before each retry.
+// The client's default Timeout is not applied, since large uploads can take a long time; bound the call with ctx instead.
+func (c *OllamaClient) CreateBlob(ctx context.Context, digest string, body io.ReadSeeker, size int64) error {
+	return c.createBlob(ctx, digest, body, size, nil)
+}
+
+// UploadBlob streams the file at path to the server and returns its digest, for use in the files or adapters of a
+// create request. The upload is skipped if the server already has the blob. Progress is reported to fn (which may
+// be nil) with the status "uploading" and finally "success".
+func (c *OllamaClient) UploadBlob(ctx context.Context, path string, fn func(ProgressEvent)) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	digest, err := readerDigest(f)
+	if err != nil {
+		return "", err
+	}
+
+	tracker := NewProgressTracker()
+	report := func(status string, completed int64) {
+		if fn != nil {
+			fn(tracker.Update(structures.ProgressResponse{Status: status, Digest: digest, Total: info.Size(), Completed: completed}))
+		}
+	}
+
+	exists, err := c.BlobExists(ctx, digest)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		if err := c.createBlob(ctx, digest, f, info.Size(), func(completed int64) { report("uploading", completed) }); err != nil {
+			return "", err
+		}
+	}
+	report("success", info.Size())
+	return digest, nil
+}
+
+// createBlob implements CreateBlob, passing the bytes sent so far in the current attempt to onProgress.
+func (c *OllamaClient) createBlob(ctx context.Context, digest string, body io.ReadSeeker, size int64, onProgress func(int64)) error {
+	if !digestPattern.MatchString(digest) {
+		return fmt.Errorf("invalid blob digest %q", digest)
+	}
+	call := &Call{Method: "POST", Endpoint: "/api/blobs/" + digest}
+
+	_, err := c.invoke(ctx, call, func(ctx context.Context, call *Call) (interface{}, error) {
+		return nil, c.withRetry(ctx, call.Endpoint, func() error {
+			if _, err := body.Seek(0, io.SeekStart); err != nil {
+				return &noRetryError{err: err}
+			}
+
+			var reader io.Reader = io.LimitReader(body, size)
+			if onProgress != nil {
+				reader = &progressReader{r: reader, fn: onProgress}
+			}
+			req, err := http.NewRequestWithContext(ctx, call.Method, c.baseURL(ctx)+call.Endpoint, reader)
+			if err != nil {
+				return &noRetryError{err: err}
+			}
+			req.ContentLength = size
+			req.Header.Set("Content-Type", "application/octet-stream")
+
+			resp, err := c.send(ctx, req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			_, _ = io.Copy(io.Discard, resp.Body)
+			return nil
+		})
+	})
+	return err
+}
+
+// progressReader reports the running total of bytes read.
+type progressReader struct {
+	r  io.Reader
+	n  int64
+	fn func(int64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		p.fn(p.n)
+	}
+	return n, err
+}
@@ -1,24 +1,36 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // Chat handles both streaming and non-streaming chat interactions.
 func (c *OllamaClient) Chat(req structures.ChatRequest, callback func(structures.ChatResponse)) (*structures.ChatResponse, error) {
+	return c.ChatContext(context.Background(), req, callback)
+}
+
+// ChatContext is like Chat but cancels the request when ctx is done.
+// When streaming, the callback may be nil and the chunks are aggregated into the returned response.
+func (c *OllamaClient) ChatContext(ctx context.Context, req structures.ChatRequest, callback func(structures.ChatResponse)) (*structures.ChatResponse, error) {
 	if req.Stream {
 		// Handle streaming response
-		return nil, c.StreamRequest("POST", "/api/chat", req, func(data json.RawMessage) {
-			var chatResp structures.ChatResponse
-			if err := json.Unmarshal(data, &chatResp); err == nil {
-				callback(chatResp)
+		var aggregate ChatAggregator
+		received := false
+		last, err := streamTrip(ctx, c, "POST", "/api/chat", req, func(chunk *structures.ChatResponse) error {
+			received = true
+			aggregate.Add(*chunk)
+			if callback != nil {
+				callback(*chunk)
 			}
+			return nil
 		})
+		if !received {
+			return last, err // An interceptor may answer without streaming any chunks
+		}
+		return aggregate.Response(), err
 	}
 
 	// Handle normal response
-	var resp structures.ChatResponse
-	err := c.Request("POST", "/api/chat", req, &resp)
-	return &resp, err
+	return roundTrip[structures.ChatResponse](ctx, c, "POST", "/api/chat", req)
 }
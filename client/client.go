@@ -3,112 +3,308 @@ package client
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/utils"
 	"io"
 	"net/http"
+	"time"
 )
 
+// maxErrorBodySize caps how much of an error response body is read.
+const maxErrorBodySize = 64 << 10
+
 // OllamaClient provides a structured API client for communicating with the Ollama API.
 type OllamaClient struct {
 	BaseURL    string
 	HTTPClient *http.Client
+	Timeout    time.Duration      // Default per-call timeout, applied when the context has no deadline.
+	Retry      config.RetryPolicy // Retry policy for transient failures.
+	Auth       Authenticator      // Optional: Adds credentials to every request.
+
+	breaker      *circuitBreaker
+	interceptors []Interceptor
+	ensures      ensureGroup
 }
 
 // NewClient initializes a new Ollama API client with default settings.
+// A non-empty cfg.APIKey is sent as a bearer token.
 func NewClient(cfg *config.Config) *OllamaClient {
-	return &OllamaClient{
-		BaseURL: cfg.BaseURL,
-		HTTPClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
+	c := &OllamaClient{
+		BaseURL:    cfg.BaseURL,
+		HTTPClient: &http.Client{},
+		Timeout:    cfg.Timeout,
+		Retry:      cfg.Retry,
+		breaker:    newCircuitBreaker(cfg.CircuitBreaker),
+	}
+	if cfg.APIKey != "" {
+		c.Auth = BearerToken(cfg.APIKey)
 	}
+	return c
 }
 
 // Request handles normal HTTP requests (non-streaming).
 func (c *OllamaClient) Request(method, endpoint string, body interface{}, response interface{}) error {
-	url := c.BaseURL + endpoint
+	return c.RequestContext(context.Background(), method, endpoint, body, response)
+}
 
-	var reqBody []byte
-	if body != nil {
-		var err error
-		reqBody, err = json.Marshal(body)
-		if err != nil {
-			return err
-		}
-	}
+// RequestContext is like Request but aborts the call when ctx is done.
+func (c *OllamaClient) RequestContext(ctx context.Context, method, endpoint string, body interface{}, response interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
+	reqBody, err := marshalBody(body)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	return c.withRetry(ctx, endpoint, func() error {
+		return c.request(ctx, method, endpoint, reqBody, response)
+	})
+}
 
-	resp, err := c.HTTPClient.Do(req)
+// request performs a single attempt of RequestContext.
+func (c *OllamaClient) request(ctx context.Context, method, endpoint string, reqBody []byte, response interface{}) error {
+	resp, err := c.do(ctx, method, endpoint, reqBody)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return errors.New("API request failed with status: " + resp.Status)
+	// Decode the response, surfacing an {"error": "..."} body even on a 2xx status
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		if err == io.EOF && response == nil {
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return wrapContextError(ctxErr)
+		}
+		return fmt.Errorf("%w: %v", utils.ErrInvalidResponse, err)
 	}
-
-	// Decode the response
-	return json.NewDecoder(resp.Body).Decode(response)
+	if apiErr := chunkError(resp, raw); apiErr != nil {
+		return apiErr
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.Unmarshal(raw, response); err != nil {
+		return fmt.Errorf("%w: %v", utils.ErrInvalidResponse, err)
+	}
+	return nil
 }
 
 // StreamRequest handles streaming HTTP responses.
 func (c *OllamaClient) StreamRequest(method, endpoint string, body interface{}, callback func(json.RawMessage)) error {
-	url := c.BaseURL + endpoint
+	return c.StreamRequestContext(context.Background(), method, endpoint, body, callback)
+}
 
-	var reqBody []byte
-	if body != nil {
-		var err error
-		reqBody, err = json.Marshal(body)
-		if err != nil {
-			return err
-		}
-	}
+// StreamRequestContext is like StreamRequest but stops reading the stream as soon as ctx is done.
+func (c *OllamaClient) StreamRequestContext(ctx context.Context, method, endpoint string, body interface{}, callback func(json.RawMessage)) error {
+	return c.streamContext(ctx, method, endpoint, body, func(message json.RawMessage) error {
+		callback(message)
+		return nil
+	})
+}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
+// streamContext implements StreamRequestContext; an error returned by callback stops the stream and is returned as is.
+func (c *OllamaClient) streamContext(ctx context.Context, method, endpoint string, body interface{}, callback func(json.RawMessage) error) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	reqBody, err := marshalBody(body)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	// Retries are only safe until the first chunk has been handed to the callback.
+	delivered := false
+	return c.withRetry(ctx, endpoint, func() error {
+		err := c.stream(ctx, method, endpoint, reqBody, func(message json.RawMessage) error {
+			delivered = true
+			return callback(message)
+		})
+		if err != nil && delivered {
+			return &noRetryError{err: err}
+		}
+		return err
+	})
+}
 
-	resp, err := c.HTTPClient.Do(req)
+// stream performs a single attempt of StreamRequestContext.
+func (c *OllamaClient) stream(ctx context.Context, method, endpoint string, reqBody []byte, callback func(json.RawMessage) error) error {
+	resp, err := c.do(ctx, method, endpoint, reqBody)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode >= 400 {
-		return errors.New("API request failed with status: " + resp.Status)
-	}
-
 	// Process the streaming response
 	reader := bufio.NewReader(resp.Body)
 	for {
+		if err := ctx.Err(); err != nil {
+			return wrapContextError(err)
+		}
+
 		line, err := reader.ReadBytes('\n')
-		if err != nil {
-			if err == io.EOF {
-				break // End of stream
+		if err != nil && err != io.EOF {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return wrapContextError(ctxErr)
 			}
 			return err
 		}
 
 		// Process JSON chunk
-		var message json.RawMessage
-		if err := json.Unmarshal(line, &message); err != nil {
-			return err
+		if len(bytes.TrimSpace(line)) > 0 {
+			var message json.RawMessage
+			if err := json.Unmarshal(line, &message); err != nil {
+				return fmt.Errorf("%w: %v", utils.ErrInvalidResponse, err)
+			}
+			if apiErr := chunkError(resp, message); apiErr != nil {
+				return apiErr
+			}
+			if err := callback(message); err != nil {
+				return err
+			}
 		}
 
-		callback(message)
+		if err == io.EOF {
+			break // End of stream
+		}
 	}
 
 	return nil
 }
+
+// marshalBody encodes a request payload once so it can be resent on retries.
+func marshalBody(body interface{}) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	return json.Marshal(body)
+}
+
+// do sends a JSON request bound to ctx and rejects error statuses.
+func (c *OllamaClient) do(ctx context.Context, method, endpoint string, reqBody []byte) (*http.Response, error) {
+	url := c.baseURL(ctx) + endpoint
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	return c.send(ctx, req)
+}
+
+// send authenticates and sends req, rejecting error statuses.
+func (c *OllamaClient) send(ctx context.Context, req *http.Request) (*http.Response, error) {
+	if c.Auth != nil {
+		if err := c.Auth.Authenticate(req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, wrapContextError(err)
+		}
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		if invalidator, ok := c.Auth.(interface{ Invalidate() }); ok && resp.StatusCode == http.StatusUnauthorized {
+			invalidator.Invalidate()
+		}
+		return nil, newAPIError(resp)
+	}
+
+	return resp, nil
+}
+
+// newAPIError builds an APIError from a failed response, keeping the server's error message.
+func newAPIError(resp *http.Response) *utils.APIError {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if apiErr := chunkError(resp, data); apiErr != nil {
+		return apiErr
+	}
+	return apiError(resp, string(bytes.TrimSpace(data)))
+}
+
+// chunkError reports an {"error": "..."} object, which the server may also send with a 2xx status.
+func chunkError(resp *http.Response, data []byte) *utils.APIError {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil || body.Error == "" {
+		return nil
+	}
+	return apiError(resp, body.Error)
+}
+
+// apiError describes resp and the request that produced it as an APIError.
+func apiError(resp *http.Response, message string) *utils.APIError {
+	return &utils.APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Message:    message,
+		Method:     resp.Request.Method,
+		Endpoint:   resp.Request.URL.Path,
+		RequestID:  requestID(resp),
+		RetryAfter: retryAfter(resp),
+	}
+}
+
+// requestID returns the request ID reported by the server or an intermediate proxy.
+func requestID(resp *http.Response) string {
+	for _, header := range []string{"X-Request-Id", "X-Correlation-Id"} {
+		if id := resp.Header.Get(header); id != "" {
+			return id
+		}
+		if id := resp.Request.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// wrapContextError marks deadline expiry as ErrTimeout while keeping the original error.
+func wrapContextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", utils.ErrTimeout, err)
+	}
+	return err
+}
+
+// hostKey is the context key of the host set by WithHost.
+type hostKey struct{}
+
+// WithHost returns a context whose calls are sent to baseURL instead of the client's BaseURL.
+// A Pool also leaves such calls where they are instead of routing them.
+func WithHost(ctx context.Context, baseURL string) context.Context {
+	return context.WithValue(ctx, hostKey{}, baseURL)
+}
+
+// baseURL returns the host set by WithHost, or BaseURL.
+func (c *OllamaClient) baseURL(ctx context.Context) string {
+	if host, ok := ctx.Value(hostKey{}).(string); ok {
+		return host
+	}
+	return c.BaseURL
+}
+
+// noTimeoutKey marks a context whose calls bound themselves instead of taking the client's default timeout.
+type noTimeoutKey struct{}
+
+// withTimeout applies the client's default timeout unless ctx already carries its own deadline.
+func (c *OllamaClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.Timeout <= 0 || ctx.Value(noTimeoutKey{}) != nil {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
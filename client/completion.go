@@ -1,24 +1,36 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // GenerateCompletion handles both streaming and non-streaming text generation.
 func (c *OllamaClient) GenerateCompletion(req structures.CompletionRequest, callback func(structures.CompletionResponse)) (*structures.CompletionResponse, error) {
+	return c.GenerateCompletionContext(context.Background(), req, callback)
+}
+
+// GenerateCompletionContext is like GenerateCompletion but cancels the request when ctx is done.
+// When streaming, the callback may be nil and the chunks are aggregated into the returned response.
+func (c *OllamaClient) GenerateCompletionContext(ctx context.Context, req structures.CompletionRequest, callback func(structures.CompletionResponse)) (*structures.CompletionResponse, error) {
 	if req.Stream {
 		// Handle streaming response
-		return nil, c.StreamRequest("POST", "/api/generate", req, func(data json.RawMessage) {
-			var completionResp structures.CompletionResponse
-			if err := json.Unmarshal(data, &completionResp); err == nil {
-				callback(completionResp)
+		var aggregate CompletionAggregator
+		received := false
+		last, err := streamTrip(ctx, c, "POST", "/api/generate", req, func(chunk *structures.CompletionResponse) error {
+			received = true
+			aggregate.Add(*chunk)
+			if callback != nil {
+				callback(*chunk)
 			}
+			return nil
 		})
+		if !received {
+			return last, err // An interceptor may answer without streaming any chunks
+		}
+		return aggregate.Response(), err
 	}
 
 	// Handle normal response
-	var resp structures.CompletionResponse
-	err := c.Request("POST", "/api/generate", req, &resp)
-	return &resp, err
+	return roundTrip[structures.CompletionResponse](ctx, c, "POST", "/api/generate", req)
 }
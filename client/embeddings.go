@@ -1,12 +1,16 @@
 package client
 
 import (
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // GenerateEmbeddings retrieves text embeddings from the API.
 func (c *OllamaClient) GenerateEmbeddings(req structures.EmbeddingRequest) (*structures.EmbeddingResponse, error) {
-	var resp structures.EmbeddingResponse
-	err := c.Request("POST", "/api/embed", req, &resp)
-	return &resp, err
+	return c.GenerateEmbeddingsContext(context.Background(), req)
+}
+
+// GenerateEmbeddingsContext is like GenerateEmbeddings but cancels the request when ctx is done.
+func (c *OllamaClient) GenerateEmbeddingsContext(ctx context.Context, req structures.EmbeddingRequest) (*structures.EmbeddingResponse, error) {
+	return roundTrip[structures.EmbeddingResponse](ctx, c, "POST", "/api/embed", req)
 }
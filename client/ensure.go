@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"strings"
+	"sync"
+)
+
+// minDigestPrefix is the shortest digest prefix accepted as a pin (the length shown by `ollama list`).
+const minDigestPrefix = 12
+
+// EnsureModel makes sure the model named by ref is available locally and returns its details.
+//
+// ref is a model name, optionally pinned to a manifest digest: "llama3.2:3b@sha256:a80c4f17acd5" (the
+// "sha256:" prefix is optional and a prefix of at least 12 hex digits is enough). The model is pulled only
+// if it is missing or its digest differs from the pin; after a pull the digest is checked again and
+// utils.ErrDigestMismatch is returned if the registry served something else. The digest comes from the
+// tags listing (ListModels) rather than ShowModel, since the show response does not include it.
+//
+// Concurrent calls for the same model share a single pull, whatever their pins, and each caller's fn (which
+// may be nil) receives its progress. The pull is bounded by the client's Timeout between progress updates
+// only, and it keeps running while any caller is still waiting: a caller whose ctx is done stops waiting
+// without failing the others.
+func (c *OllamaClient) EnsureModel(ctx context.Context, ref string, fn func(ProgressEvent)) (*structures.ModelInfo, error) {
+	name, pin, err := parseModelRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		// A shared call satisfies the pin of the caller that started it; others check theirs against
+		// its result and start their own round if it did not pull
+		info, pulled, err := c.ensures.do(ctx, c.baseURL(ctx)+" "+name, fn, func(ctx context.Context, broadcast func(ProgressEvent)) (*structures.ModelInfo, bool, error) {
+			return c.ensure(ctx, name, pin, broadcast)
+		})
+		switch {
+		case err != nil:
+			return info, err
+		case digestMatches(info.Digest, pin):
+			return info, nil
+		case pulled:
+			return info, fmt.Errorf("%w: %s has digest %s, want %s", utils.ErrDigestMismatch, name, info.Digest, pin)
+		}
+	}
+}
+
+// ensure pulls name unless it is present with a digest matching pin, and returns its details and
+// whether it was pulled.
+func (c *OllamaClient) ensure(ctx context.Context, name, pin string, fn func(ProgressEvent)) (*structures.ModelInfo, bool, error) {
+	info, err := c.findModel(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if info != nil && digestMatches(info.Digest, pin) {
+		return info, false, nil
+	}
+
+	if _, err := c.PullModelProgress(ctx, structures.PullRequest{Model: name}, fn); err != nil {
+		return nil, false, err
+	}
+
+	info, err = c.findModel(ctx, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if info == nil {
+		return nil, false, fmt.Errorf("%w: %s is not listed after pulling", utils.ErrModelNotFound, name)
+	}
+	return info, true, nil
+}
+
+// findModel returns the local model called name, or nil if there is none.
+func (c *OllamaClient) findModel(ctx context.Context, name string) (*structures.ModelInfo, error) {
+	list, err := c.ListModelsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list.Models {
+		if normalizeModelName(list.Models[i].Name) == name {
+			return &list.Models[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// parseModelRef splits "name[@[sha256:]digest]" into a normalized name and a lowercase hex digest.
+func parseModelRef(ref string) (name, digest string, err error) {
+	name, digest, _ = strings.Cut(strings.TrimSpace(ref), "@")
+	if name == "" {
+		return "", "", fmt.Errorf("invalid model reference %q: missing name", ref)
+	}
+	digest = strings.ToLower(strings.TrimPrefix(digest, "sha256:"))
+	if digest != "" {
+		if len(digest) < minDigestPrefix || strings.Trim(digest, "0123456789abcdef") != "" {
+			return "", "", fmt.Errorf("invalid model reference %q: digest must be at least %d hex digits", ref, minDigestPrefix)
+		}
+	}
+	return normalizeModelName(name), digest, nil
+}
+
+// normalizeModelName adds the implicit ":latest" tag.
+func normalizeModelName(name string) string {
+	if i := strings.LastIndex(name, "/"); strings.Contains(name[i+1:], ":") {
+		return name
+	}
+	return name + ":latest"
+}
+
+// digestMatches reports whether digest satisfies the pin (any digest does if pin is empty).
+func digestMatches(digest, pin string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(strings.ToLower(digest), "sha256:"), pin)
+}
+
+// ensureGroup deduplicates concurrent EnsureModel calls for the same model on the same host.
+type ensureGroup struct {
+	mu    sync.Mutex
+	calls map[string]*ensureCall
+}
+
+// ensureCall is an in-flight EnsureModel shared by every caller of the same model.
+type ensureCall struct {
+	done    chan struct{}
+	info    *structures.ModelInfo
+	pulled  bool
+	err     error
+	cancel  context.CancelFunc
+	waiters int // Callers still waiting; the call is cancelled when the last one leaves.
+
+	mu        sync.Mutex
+	nextID    int
+	listeners map[int]func(ProgressEvent)
+}
+
+// do runs fn once per key at a time; callers arriving while it runs wait for its result. fn runs with a
+// context that keeps ctx's values but is only cancelled once every waiting caller's ctx is done.
+func (g *ensureGroup) do(ctx context.Context, key string, listener func(ProgressEvent),
+	fn func(ctx context.Context, broadcast func(ProgressEvent)) (*structures.ModelInfo, bool, error)) (*structures.ModelInfo, bool, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*ensureCall{}
+	}
+	call, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &ensureCall{done: make(chan struct{}), cancel: cancel, listeners: map[int]func(ProgressEvent){}}
+		g.calls[key] = call
+		go func() {
+			call.info, call.pulled, call.err = fn(callCtx, call.broadcast)
+			cancel()
+			g.mu.Lock()
+			if g.calls[key] == call {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			close(call.done)
+		}()
+	}
+	call.waiters++
+	id := call.listen(listener)
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.info, call.pulled, call.err
+	case <-ctx.Done():
+		call.unlisten(id)
+		g.mu.Lock()
+		if call.waiters--; call.waiters == 0 {
+			call.cancel()
+			if g.calls[key] == call {
+				delete(g.calls, key) // Later callers start afresh rather than joining a cancelled call
+			}
+		}
+		g.mu.Unlock()
+		return nil, false, wrapContextError(ctx.Err())
+	}
+}
+
+// listen adds a listener for progress events and returns its ID.
+func (c *ensureCall) listen(listener func(ProgressEvent)) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	if listener != nil {
+		c.listeners[c.nextID] = listener
+	}
+	return c.nextID
+}
+
+// unlisten removes a listener whose caller stopped waiting.
+func (c *ensureCall) unlisten(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.listeners, id)
+}
+
+// broadcast passes a progress event to every waiting caller.
+func (c *ensureCall) broadcast(event ProgressEvent) {
+	c.mu.Lock()
+	listeners := make([]func(ProgressEvent), 0, len(c.listeners))
+	for _, listener := range c.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.mu.Unlock()
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
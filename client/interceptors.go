@@ -0,0 +1,137 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/utils"
+	"time"
+)
+
+// Call describes a single API call as it passes through the interceptor chain.
+type Call struct {
+	Method   string                        // HTTP method (e.g., "POST").
+	Endpoint string                        // API endpoint (e.g., "/api/chat").
+	Request  interface{}                   // Request payload (e.g., structures.ChatRequest); may be replaced by a value of the same type. Nil for blob uploads, whose body is streamed.
+	Stream   bool                          // Whether the response arrives as a stream of chunks.
+	OnChunk  func(chunk interface{}) error // Streaming only: receives each decoded chunk (e.g., *structures.ChatResponse).
+}
+
+// Handler performs a call and returns its decoded response (e.g., *structures.ChatResponse).
+// Calls without a response body return nil. For streams, the response is the final chunk.
+type Handler func(ctx context.Context, call *Call) (interface{}, error)
+
+// Interceptor wraps every API call made by the client. It can observe or rewrite the call,
+// wrap call.OnChunk to inspect stream chunks, short-circuit by returning without calling next,
+// or inspect and replace the response returned by next.
+type Interceptor func(ctx context.Context, call *Call, next Handler) (interface{}, error)
+
+// Use appends interceptors to the client's chain. Interceptors run in the order they were added:
+// the first one sees the call first and the response last.
+// Use is not safe to call concurrently with requests.
+func (c *OllamaClient) Use(interceptors ...Interceptor) {
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// LoggingInterceptor logs every call with its duration and outcome using the utils logger.
+func LoggingInterceptor() Interceptor {
+	return func(ctx context.Context, call *Call, next Handler) (interface{}, error) {
+		start := time.Now()
+		resp, err := next(ctx, call)
+		if err != nil {
+			utils.Error(call.Method, call.Endpoint, "failed after", time.Since(start), err)
+		} else {
+			utils.Info(call.Method, call.Endpoint, "completed in", time.Since(start))
+		}
+		return resp, err
+	}
+}
+
+// invoke runs call through the interceptor chain, ending with terminal.
+// Requests that can validate themselves are checked before anything is sent.
+func (c *OllamaClient) invoke(ctx context.Context, call *Call, terminal Handler) (interface{}, error) {
+	handler := func(ctx context.Context, call *Call) (interface{}, error) {
+		if v, ok := call.Request.(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return terminal(ctx, call)
+	}
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.interceptors[i], handler
+		handler = func(ctx context.Context, call *Call) (interface{}, error) {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return handler(ctx, call)
+}
+
+// roundTrip performs a non-streaming call whose response decodes into T.
+func roundTrip[T any](ctx context.Context, c *OllamaClient, method, endpoint string, req interface{}) (*T, error) {
+	call := &Call{Method: method, Endpoint: endpoint, Request: req}
+	resp, err := c.invoke(ctx, call, func(ctx context.Context, call *Call) (interface{}, error) {
+		var resp T
+		err := c.RequestContext(ctx, call.Method, call.Endpoint, call.Request, &resp)
+		return &resp, err
+	})
+	return responseAs[T](resp, err)
+}
+
+// exec performs a call that has no response body.
+func (c *OllamaClient) exec(ctx context.Context, method, endpoint string, req interface{}) error {
+	call := &Call{Method: method, Endpoint: endpoint, Request: req}
+	_, err := c.invoke(ctx, call, func(ctx context.Context, call *Call) (interface{}, error) {
+		return nil, c.RequestContext(ctx, call.Method, call.Endpoint, call.Request, nil)
+	})
+	return err
+}
+
+// streamTrip performs a streaming call whose chunks decode into T, passing each one to onChunk.
+// It returns the last chunk received.
+func streamTrip[T any](ctx context.Context, c *OllamaClient, method, endpoint string, req interface{}, onChunk func(*T) error) (*T, error) {
+	call := &Call{
+		Method:   method,
+		Endpoint: endpoint,
+		Request:  req,
+		Stream:   true,
+		OnChunk: func(chunk interface{}) error {
+			typed, ok := chunk.(*T)
+			if !ok {
+				return fmt.Errorf("%w: unexpected chunk type %T", utils.ErrInvalidResponse, chunk)
+			}
+			return onChunk(typed)
+		},
+	}
+	resp, err := c.invoke(ctx, call, func(ctx context.Context, call *Call) (interface{}, error) {
+		var last *T
+		err := c.streamContext(ctx, call.Method, call.Endpoint, call.Request, func(data json.RawMessage) error {
+			chunk := new(T)
+			if err := json.Unmarshal(data, chunk); err != nil {
+				return fmt.Errorf("%w: %v", utils.ErrInvalidResponse, err)
+			}
+			last = chunk
+			return call.OnChunk(chunk)
+		})
+		if last == nil {
+			return nil, err
+		}
+		return last, err
+	})
+	if resp == nil {
+		return nil, err
+	}
+	return responseAs[T](resp, err)
+}
+
+// responseAs converts a response returned by the interceptor chain back to its concrete type.
+func responseAs[T any](resp interface{}, err error) (*T, error) {
+	typed, ok := resp.(*T)
+	if !ok || typed == nil {
+		if err == nil {
+			err = fmt.Errorf("%w: unexpected response type %T", utils.ErrInvalidResponse, resp)
+		}
+		return new(T), err
+	}
+	return typed, err
+}
@@ -0,0 +1,18 @@
+package client
+
+import (
+	"context"
+	"github.com/SamyRai/ollama-go/structures"
+)
+
+// LoadModel loads a model into memory without generating anything, keeping it loaded for keepAlive
+// (the server default if nil). Embedding-only models cannot be loaded this way.
+func (c *OllamaClient) LoadModel(ctx context.Context, model string, keepAlive *structures.KeepAlive) error {
+	req := structures.CompletionRequest{Model: model, KeepAlive: keepAlive}
+	return c.exec(ctx, "POST", "/api/generate", req)
+}
+
+// UnloadModel unloads a model from memory immediately.
+func (c *OllamaClient) UnloadModel(ctx context.Context, model string) error {
+	return c.LoadModel(ctx, model, structures.KeepAliveFor(0))
+}
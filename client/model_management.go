@@ -1,34 +1,79 @@
 package client
 
 import (
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // CreateModel sends a request to create a new model.
 func (c *OllamaClient) CreateModel(req structures.ModelManagementRequest) error {
-	return c.Request("POST", "/api/create", req, nil)
+	return c.CreateModelContext(context.Background(), req)
+}
+
+// CreateModelContext is like CreateModel but cancels the request when ctx is done.
+func (c *OllamaClient) CreateModelContext(ctx context.Context, req structures.ModelManagementRequest) error {
+	req.Stream = structures.Ptr(false)
+	return c.exec(ctx, "POST", "/api/create", req)
+}
+
+// CreateModelProgress creates a model, reporting each status update to fn (which may be nil).
+func (c *OllamaClient) CreateModelProgress(ctx context.Context, req structures.ModelManagementRequest, fn func(ProgressEvent)) (*structures.ProgressResponse, error) {
+	req.Stream = nil
+	return c.progress(ctx, "/api/create", req, fn)
 }
 
 // DeleteModel sends a request to delete an existing model.
 func (c *OllamaClient) DeleteModel(modelName string) error {
-	payload := map[string]string{"model": modelName}
-	return c.Request("DELETE", "/api/delete", payload, nil)
+	return c.DeleteModelContext(context.Background(), modelName)
+}
+
+// DeleteModelContext is like DeleteModel but cancels the request when ctx is done.
+func (c *OllamaClient) DeleteModelContext(ctx context.Context, modelName string) error {
+	return c.exec(ctx, "DELETE", "/api/delete", structures.DeleteRequest{Model: modelName})
 }
 
 // CopyModel copies a model to a new name.
 func (c *OllamaClient) CopyModel(sourceModel, targetModel string) error {
-	payload := map[string]string{"sourceModel": sourceModel, "targetModel": targetModel}
-	return c.Request("POST", "/api/copy", payload, nil)
+	return c.CopyModelContext(context.Background(), sourceModel, targetModel)
+}
+
+// CopyModelContext is like CopyModel but cancels the request when ctx is done.
+func (c *OllamaClient) CopyModelContext(ctx context.Context, sourceModel, targetModel string) error {
+	return c.exec(ctx, "POST", "/api/copy", structures.CopyRequest{Source: sourceModel, Destination: targetModel})
 }
 
 // PullModel pulls a model from a remote repository.
 func (c *OllamaClient) PullModel(modelName string) error {
-	payload := map[string]string{"model": modelName}
-	return c.Request("POST", "/api/pull", payload, nil)
+	return c.PullModelContext(context.Background(), modelName)
+}
+
+// PullModelContext is like PullModel but cancels the request when ctx is done.
+func (c *OllamaClient) PullModelContext(ctx context.Context, modelName string) error {
+	payload := structures.PullRequest{Model: modelName, Stream: structures.Ptr(false)}
+	return c.exec(ctx, "POST", "/api/pull", payload)
+}
+
+// PullModelProgress pulls a model, reporting per-layer and overall progress to fn (which may be nil).
+// The server keeps partially downloaded layers, so calling it again after a cancellation resumes the pull.
+// The client's Timeout bounds the wait between updates, not the whole pull.
+func (c *OllamaClient) PullModelProgress(ctx context.Context, req structures.PullRequest, fn func(ProgressEvent)) (*structures.ProgressResponse, error) {
+	req.Stream = nil
+	return c.progress(ctx, "/api/pull", req, fn)
 }
 
 // PushModel pushes a model to a remote repository.
 func (c *OllamaClient) PushModel(modelName string) error {
-	payload := map[string]string{"model": modelName}
-	return c.Request("POST", "/api/push", payload, nil)
+	return c.PushModelContext(context.Background(), modelName)
+}
+
+// PushModelContext is like PushModel but cancels the request when ctx is done.
+func (c *OllamaClient) PushModelContext(ctx context.Context, modelName string) error {
+	payload := structures.PushRequest{Model: modelName, Stream: structures.Ptr(false)}
+	return c.exec(ctx, "POST", "/api/push", payload)
+}
+
+// PushModelProgress pushes a model, reporting per-layer and overall progress to fn (which may be nil).
+func (c *OllamaClient) PushModelProgress(ctx context.Context, req structures.PushRequest, fn func(ProgressEvent)) (*structures.ProgressResponse, error) {
+	req.Stream = nil
+	return c.progress(ctx, "/api/push", req, fn)
 }
@@ -1,19 +1,26 @@
 package client
 
 import (
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // ListModels retrieves all available models.
 func (c *OllamaClient) ListModels() (*structures.ModelListResponse, error) {
-	var resp structures.ModelListResponse
-	err := c.Request("GET", "/api/tags", nil, &resp)
-	return &resp, err
+	return c.ListModelsContext(context.Background())
+}
+
+// ListModelsContext is like ListModels but cancels the request when ctx is done.
+func (c *OllamaClient) ListModelsContext(ctx context.Context) (*structures.ModelListResponse, error) {
+	return roundTrip[structures.ModelListResponse](ctx, c, "GET", "/api/tags", nil)
 }
 
 // ShowModel retrieves details about a specific model.
 func (c *OllamaClient) ShowModel(req structures.ShowModelRequest) (*structures.ShowModelResponse, error) {
-	var resp structures.ShowModelResponse
-	err := c.Request("POST", "/api/show", req, &resp)
-	return &resp, err
+	return c.ShowModelContext(context.Background(), req)
+}
+
+// ShowModelContext is like ShowModel but cancels the request when ctx is done.
+func (c *OllamaClient) ShowModelContext(ctx context.Context, req structures.ShowModelRequest) (*structures.ShowModelResponse, error) {
+	return roundTrip[structures.ShowModelResponse](ctx, c, "POST", "/api/show", req)
 }
@@ -0,0 +1,387 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pool defaults.
+const (
+	DefaultPoolInterval   = 15 * time.Second
+	DefaultPoolEjectAfter = 3
+	DefaultPoolCooldown   = 30 * time.Second
+)
+
+// Pool is an OllamaClient that spreads calls across several Ollama hosts. Each call that names a model goes to
+// a healthy host that has the model loaded, else one that has it on disk, else the least busy one; calls
+// without a model go to the least busy host. A call that fails with a transient error, or because the model
+// is missing, is retried on the next best host, unless a stream has already delivered chunks.
+//
+// Which models each host has comes from Check, which Run repeats every Interval, and from the calls
+// themselves. A host is ejected when a check fails or after EjectAfter consecutive failed calls. It is
+// readmitted by the next successful check, or, once Cooldown has passed, by a call that succeeds on it:
+// after each cool-down a single call may be routed to the host to probe it.
+//
+// Methods that make several calls are routed one call at a time, except EnsureModel, which Pool runs on a
+// single host. Calls under a context from WithHost bypass routing, e.g. to upload blobs and create a model
+// on one host.
+// Blob checks and uploads without a host go to BaseURL, the first host. Interceptors added with Use run once
+// per attempt.
+type Pool struct {
+	*OllamaClient
+	Interval   time.Duration                // How often Run checks the hosts (DefaultPoolInterval if zero).
+	EjectAfter int                          // Consecutive failed calls that eject a host (DefaultPoolEjectAfter if zero).
+	Cooldown   time.Duration                // How long an ejected host gets no calls before one probes it (DefaultPoolCooldown if zero).
+	OnEject    func(host string, err error) // Optional: Called when a host is ejected.
+
+	mu    sync.Mutex
+	hosts []*poolHost
+}
+
+// poolHost is the routing state of one host.
+type poolHost struct {
+	url       string
+	healthy   bool
+	failures  int       // Consecutive failed calls.
+	ejectedAt time.Time // When the host was ejected or last probed.
+	inFlight  int
+	served    int // Calls routed to the host, to spread ties.
+	loaded    map[string]bool
+	installed map[string]bool
+}
+
+// HostStatus is a snapshot of one host of a Pool.
+type HostStatus struct {
+	URL       string
+	Healthy   bool
+	InFlight  int      // Calls in progress.
+	Loaded    []string // Models in memory, as of the last check or call.
+	Installed []string // Models on disk, as of the last check or call.
+}
+
+// NewPool creates a pool of hosts (base URLs such as "http://gpu1:11434") sharing cfg's retry status codes
+// and credentials. cfg.BaseURL is ignored. Calls are not retried on the same host and the circuit breaker is
+// disabled, since failing over to another host and ejection take their place. cfg.Timeout is ignored too:
+// long generations are normal on a busy pool, so calls are only bounded by their context.
+func NewPool(cfg *config.Config, hosts ...string) *Pool {
+	shared := *cfg
+	shared.Timeout = 0
+	shared.Retry.MaxRetries = 0
+	shared.CircuitBreaker = config.CircuitBreakerPolicy{}
+	if len(hosts) > 0 {
+		shared.BaseURL = hosts[0]
+	}
+
+	p := &Pool{OllamaClient: NewClient(&shared)}
+	for _, host := range hosts {
+		p.hosts = append(p.hosts, &poolHost{url: host, healthy: true, loaded: map[string]bool{}, installed: map[string]bool{}})
+	}
+	p.Use(p.route)
+	return p
+}
+
+// Hosts returns the state of every host, in the order they were given.
+func (p *Pool) Hosts() []HostStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	status := make([]HostStatus, len(p.hosts))
+	for i, h := range p.hosts {
+		status[i] = HostStatus{URL: h.url, Healthy: h.healthy, InFlight: h.inFlight,
+			Loaded: sortedNames(h.loaded), Installed: sortedNames(h.installed)}
+	}
+	return status
+}
+
+// Run checks the hosts immediately and then every Interval until ctx is done, returning ctx's error.
+func (p *Pool) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultPoolInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = p.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check refreshes the running and local models of every host in parallel, ejecting hosts that fail and
+// readmitting those that recover. It returns the failures joined together.
+func (p *Pool) Check(ctx context.Context) error {
+	p.mu.Lock()
+	hosts := append([]*poolHost(nil), p.hosts...)
+	p.mu.Unlock()
+
+	errs := make([]error, len(hosts))
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.check(ctx, h)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// check refreshes a single host.
+func (p *Pool) check(ctx context.Context, h *poolHost) error {
+	hostCtx := WithHost(ctx, h.url)
+	running, err := p.GetRunningProcessesContext(hostCtx)
+	var local *structures.ModelListResponse
+	if err == nil {
+		local, err = p.ListModelsContext(hostCtx)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			p.eject(h, err)
+		}
+		return fmt.Errorf("checking %s: %w", h.url, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h.healthy, h.failures = true, 0
+	h.loaded, h.installed = map[string]bool{}, map[string]bool{}
+	for _, m := range running.Models {
+		h.loaded[normalizeModelName(m.Name)] = true
+	}
+	for _, m := range local.Models {
+		h.installed[normalizeModelName(m.Name)] = true
+	}
+	return nil
+}
+
+// eject marks h unhealthy.
+func (p *Pool) eject(h *poolHost, err error) {
+	p.mu.Lock()
+	was := h.healthy
+	h.healthy, h.ejectedAt = false, time.Now()
+	p.mu.Unlock()
+	if was && p.OnEject != nil {
+		p.OnEject(h.url, err)
+	}
+}
+
+// route is the interceptor that sends each call to a host, failing over to the others.
+func (p *Pool) route(ctx context.Context, call *Call, next Handler) (interface{}, error) {
+	if _, ok := ctx.Value(hostKey{}).(string); ok || strings.HasPrefix(call.Endpoint, "/api/blobs/") {
+		return next(ctx, call)
+	}
+
+	model := requestModel(call.Request)
+	tried := map[*poolHost]bool{}
+	err := utils.ErrNoHealthyHosts
+	for {
+		h := p.pick(model, tried)
+		if h == nil {
+			return nil, err
+		}
+		tried[h] = true
+
+		// Each attempt gets its own copy, since interceptors further down may rewrite the call
+		attempt, delivered := *call, false
+		if call.OnChunk != nil {
+			attempt.OnChunk = func(chunk interface{}) error {
+				delivered = true
+				return call.OnChunk(chunk)
+			}
+		}
+		var resp interface{}
+		resp, err = next(WithHost(ctx, h.url), &attempt)
+		// Timeouts are the caller's or the client's own limits, not a sign that the host is down
+		p.release(h, &attempt, err, ctx.Err() == nil && serverFailure(err) && !errors.Is(err, utils.ErrTimeout))
+		if err == nil || delivered || !p.failover(ctx, call.Endpoint, model, err) {
+			return resp, err
+		}
+	}
+}
+
+// EnsureModel is like OllamaClient.EnsureModel, but lists and pulls on a single host: the best one for the
+// model as for other calls, or the one set with WithHost.
+func (p *Pool) EnsureModel(ctx context.Context, ref string, fn func(ProgressEvent)) (*structures.ModelInfo, error) {
+	if _, ok := ctx.Value(hostKey{}).(string); ok {
+		return p.OllamaClient.EnsureModel(ctx, ref, fn)
+	}
+	name, _, err := parseModelRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	h := p.pick(name, nil)
+	if h == nil {
+		return nil, utils.ErrNoHealthyHosts
+	}
+
+	info, err := p.OllamaClient.EnsureModel(WithHost(ctx, h.url), ref, fn)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h.inFlight--
+	if err == nil {
+		h.installed[name] = true
+	}
+	return info, err
+}
+
+// failover reports whether a call to endpoint that failed with err should be tried on another host.
+func (p *Pool) failover(ctx context.Context, endpoint, model string, err error) bool {
+	if model != "" && errors.Is(err, utils.ErrModelNotFound) {
+		return ctx.Err() == nil
+	}
+	return p.retryable(ctx, endpoint, err)
+}
+
+// pick reserves the best untried host for model, or returns nil if there is none. Ejected hosts are only
+// eligible once their cool-down has passed, and picking one restarts it.
+func (p *Pool) pick(model string, tried map[*poolHost]bool) *poolHost {
+	cooldown := p.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultPoolCooldown
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Hosts rank by whether they have the model loaded, then on disk, then by load
+	rank := func(h *poolHost) int {
+		switch {
+		case model == "":
+			return 2
+		case h.loaded[model]:
+			return 0
+		case h.installed[model]:
+			return 1
+		}
+		return 2
+	}
+	var best *poolHost
+	for _, h := range p.hosts {
+		if tried[h] || !h.healthy && time.Since(h.ejectedAt) < cooldown {
+			continue
+		}
+		if best == nil || rank(h) < rank(best) ||
+			rank(h) == rank(best) && (h.inFlight < best.inFlight || h.inFlight == best.inFlight && h.served < best.served) {
+			best = h
+		}
+	}
+	if best != nil {
+		best.inFlight++
+		best.served++
+		if !best.healthy {
+			best.ejectedAt = time.Now()
+		}
+	}
+	return best
+}
+
+// release ends a call on h, counting failed toward ejecting h. A success readmits h.
+func (p *Pool) release(h *poolHost, call *Call, err error, failed bool) {
+	ejectAfter := p.EjectAfter
+	if ejectAfter <= 0 {
+		ejectAfter = DefaultPoolEjectAfter
+	}
+
+	p.mu.Lock()
+	h.inFlight--
+	switch {
+	case failed:
+		h.failures++
+	case err == nil:
+		h.healthy, h.failures = true, 0
+	}
+	eject := failed && h.failures >= ejectAfter
+	learn(h, call.Request, err)
+	p.mu.Unlock()
+
+	if eject {
+		p.eject(h, err)
+	}
+}
+
+// learn updates which models h has from the outcome of a call.
+func learn(h *poolHost, req interface{}, err error) {
+	if err != nil {
+		if errors.Is(err, utils.ErrModelNotFound) {
+			delete(h.loaded, requestModel(req))
+			delete(h.installed, requestModel(req))
+		}
+		return
+	}
+
+	switch req := req.(type) {
+	case structures.ChatRequest, structures.EmbeddingRequest:
+		model := requestModel(req)
+		h.installed[model], h.loaded[model] = true, true
+	case structures.CompletionRequest:
+		model := requestModel(req)
+		h.installed[model] = true
+		if req.KeepAlive != nil && !req.KeepAlive.Forever() && req.KeepAlive.Duration() == 0 {
+			delete(h.loaded, model) // Unloaded by UnloadModel
+		} else {
+			h.loaded[model] = true
+		}
+	case structures.PullRequest:
+		h.installed[requestModel(req)] = true
+	case structures.ModelManagementRequest:
+		h.installed[normalizeModelName(req.Name)] = true
+	case structures.CopyRequest:
+		h.installed[normalizeModelName(req.Destination)] = true
+	case structures.DeleteRequest:
+		delete(h.installed, requestModel(req))
+		delete(h.loaded, requestModel(req))
+	}
+}
+
+// requestModel returns the model a call needs, normalized, or "" if it needs none.
+// Creating a model needs the model it is built from.
+func requestModel(req interface{}) string {
+	var model string
+	switch req := req.(type) {
+	case structures.ChatRequest:
+		model = req.Model
+	case structures.CompletionRequest:
+		model = req.Model
+	case structures.EmbeddingRequest:
+		model = req.Model
+	case structures.ShowModelRequest:
+		model = req.Model
+	case structures.PullRequest:
+		model = req.Model
+	case structures.PushRequest:
+		model = req.Model
+	case structures.DeleteRequest:
+		model = req.Model
+	case structures.CopyRequest:
+		model = req.Source
+	case structures.ModelManagementRequest:
+		model = req.From
+	}
+	if model == "" {
+		return ""
+	}
+	return normalizeModelName(model)
+}
+
+// sortedNames returns the keys of a model set in order.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
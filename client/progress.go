@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"sync"
+	"time"
+)
+
+// rateSmoothing is the weight of the newest sample in the exponentially smoothed transfer rate.
+const rateSmoothing = 0.3
+
+// LayerProgress is the transfer state of a single layer.
+type LayerProgress struct {
+	Digest    string  // Layer digest.
+	Total     int64   // Layer size in bytes.
+	Completed int64   // Bytes transferred so far.
+	Percent   float64 // Completed as a percentage of Total (0-100).
+}
+
+// ProgressEvent is a typed progress update for a pull, push or create.
+type ProgressEvent struct {
+	Status         string         // Server status message (e.g., "pulling manifest", "success").
+	Layer          *LayerProgress // Layer this update refers to, if any.
+	Completed      int64          // Bytes transferred across all layers seen so far.
+	Total          int64          // Total bytes across all layers seen so far.
+	Percent        float64        // Overall progress (0-100).
+	BytesPerSecond float64        // Smoothed transfer rate.
+	Elapsed        time.Duration  // Time since the first update.
+	Done           bool           // True when the server reported success.
+}
+
+// ProgressTracker turns raw status updates into ProgressEvents, tracking every layer by digest.
+// Bytes already present when a layer is first seen (e.g., when a cancelled pull is resumed)
+// count towards the percentage but not towards the transfer rate.
+type ProgressTracker struct {
+	mu       sync.Mutex
+	layers   map[string]*LayerProgress
+	order    []string
+	start    time.Time
+	lastTime time.Time
+	pending  float64
+	rate     float64
+	now      func() time.Time
+}
+
+// NewProgressTracker creates an empty tracker.
+func NewProgressTracker() *ProgressTracker {
+	return &ProgressTracker{layers: map[string]*LayerProgress{}, now: time.Now}
+}
+
+// Update records a status update and returns the resulting event.
+func (t *ProgressTracker) Update(resp structures.ProgressResponse) ProgressEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	if t.start.IsZero() {
+		t.start, t.lastTime = now, now
+	}
+
+	event := ProgressEvent{Status: resp.Status, Done: resp.Status == "success"}
+	if resp.Digest != "" {
+		layer, seen := t.layers[resp.Digest]
+		if !seen {
+			layer = &LayerProgress{Digest: resp.Digest, Completed: resp.Completed}
+			t.layers[resp.Digest] = layer
+			t.order = append(t.order, resp.Digest)
+		}
+		if resp.Total > 0 {
+			layer.Total = resp.Total
+		}
+		if delta := resp.Completed - layer.Completed; delta > 0 {
+			t.sample(float64(delta), now)
+		}
+		if resp.Completed > layer.Completed {
+			layer.Completed = resp.Completed
+		}
+		layer.Percent = percent(layer.Completed, layer.Total)
+		snapshot := *layer
+		event.Layer = &snapshot
+	}
+
+	for _, l := range t.layers {
+		event.Completed += l.Completed
+		event.Total += l.Total
+	}
+	event.Percent = percent(event.Completed, event.Total)
+	if event.Done {
+		event.Percent = 100
+	}
+	event.BytesPerSecond = t.rate
+	event.Elapsed = now.Sub(t.start)
+	return event
+}
+
+// Layers returns the state of every layer seen so far, in the order they first appeared.
+func (t *ProgressTracker) Layers() []LayerProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	layers := make([]LayerProgress, 0, len(t.order))
+	for _, digest := range t.order {
+		layers = append(layers, *t.layers[digest])
+	}
+	return layers
+}
+
+// sample folds bytes transferred since the previous sample into the smoothed rate.
+func (t *ProgressTracker) sample(bytes float64, now time.Time) {
+	t.pending += bytes
+	elapsed := now.Sub(t.lastTime).Seconds()
+	if elapsed <= 0 {
+		return // Accumulate into the next sample rather than dividing by zero
+	}
+	current := t.pending / elapsed
+	t.pending = 0
+	if t.rate == 0 {
+		t.rate = current
+	} else {
+		t.rate = rateSmoothing*current + (1-rateSmoothing)*t.rate
+	}
+	t.lastTime = now
+}
+
+// percent returns completed as a percentage of total.
+func percent(completed, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	p := float64(completed) / float64(total) * 100
+	if p > 100 {
+		return 100
+	}
+	return p
+}
+
+// progress streams status updates from endpoint, reporting each one to fn.
+// It returns the final status, or an error if the server reported one or the stream ended before success.
+// Pulls of large models take far longer than the client's default Timeout, so it only bounds the wait for
+// each update: the call fails with utils.ErrTimeout once the server has sent nothing for that long.
+func (c *OllamaClient) progress(ctx context.Context, endpoint string, req interface{}, fn func(ProgressEvent)) (*structures.ProgressResponse, error) {
+	ctx, cancel := context.WithCancelCause(context.WithValue(ctx, noTimeoutKey{}, true))
+	defer cancel(nil)
+	idle := func() {}
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		timer := time.AfterFunc(c.Timeout, func() {
+			cancel(fmt.Errorf("%w: no progress for %s", utils.ErrTimeout, c.Timeout))
+		})
+		defer timer.Stop()
+		idle = func() { timer.Reset(c.Timeout) }
+	}
+
+	tracker := NewProgressTracker()
+	last, err := streamTrip(ctx, c, "POST", endpoint, req, func(chunk *structures.ProgressResponse) error {
+		idle()
+		event := tracker.Update(*chunk)
+		if fn != nil {
+			fn(event)
+		}
+		return nil
+	})
+	if cause := context.Cause(ctx); err != nil && errors.Is(cause, utils.ErrTimeout) {
+		err = cause
+	}
+	if err != nil {
+		return last, err
+	}
+	if last == nil {
+		return nil, fmt.Errorf("%w: empty progress stream", utils.ErrInvalidResponse)
+	}
+	if last.Status != "success" {
+		return last, fmt.Errorf("%w: stream ended with status %q", utils.ErrInvalidResponse, last.Status)
+	}
+	return last, nil
+}
@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/utils"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// nonIdempotent lists the endpoints that are only retried with RetryPolicy.RetryNonIdempotent set, since a
+// failed attempt may still have taken effect on the server.
+var nonIdempotent = map[string]bool{
+	"/api/create": true,
+	"/api/copy":   true,
+	"/api/delete": true,
+}
+
+// noRetryError marks an error that must be returned without further attempts.
+type noRetryError struct {
+	err error
+}
+
+func (e *noRetryError) Error() string { return e.err.Error() }
+func (e *noRetryError) Unwrap() error { return e.err }
+
+// withRetry runs call to endpoint until it succeeds, fails permanently, or the retry policy is exhausted.
+func (c *OllamaClient) withRetry(ctx context.Context, endpoint string, call func() error) error {
+	for attempt := 0; ; attempt++ {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+
+		err := call()
+
+		var permanent *noRetryError
+		if errors.As(err, &permanent) {
+			c.breaker.record(permanent.err)
+			return permanent.err
+		}
+		c.breaker.record(err)
+
+		if err == nil || attempt >= c.Retry.MaxRetries || !c.retryable(ctx, endpoint, err) {
+			return err
+		}
+
+		timer := time.NewTimer(c.backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return wrapContextError(ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// retryable reports whether a call to endpoint that failed with err is worth another attempt.
+func (c *OllamaClient) retryable(ctx context.Context, endpoint string, err error) bool {
+	if ctx.Err() != nil || nonIdempotent[endpoint] && !c.Retry.RetryNonIdempotent {
+		return false
+	}
+
+	var apiErr *utils.APIError
+	if errors.As(err, &apiErr) {
+		for _, code := range c.Retry.RetryableStatusCodes {
+			if apiErr.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	return transportError(err)
+}
+
+// transportError reports whether err is a connection failure: the server could not be reached, or it
+// dropped the connection before the reply was complete.
+func transportError(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if urlErr.Err == io.EOF {
+			return true // Closed before any reply
+		}
+		err = urlErr.Err // *url.Error is itself a net.Error, whatever it wraps
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns the delay before the retry following attempt, honouring Retry-After.
+func (c *OllamaClient) backoff(attempt int, err error) time.Duration {
+	policy := c.Retry
+
+	var apiErr *utils.APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		if policy.MaxBackoff > 0 && apiErr.RetryAfter > policy.MaxBackoff {
+			return policy.MaxBackoff
+		}
+		return apiErr.RetryAfter
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if policy.Jitter > 0 {
+		delay += delay * policy.Jitter * (2*rand.Float64() - 1)
+	}
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+	return time.Duration(delay)
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// circuitBreaker fails requests fast after repeated server failures.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	policy   config.CircuitBreakerPolicy
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker returns a breaker for policy, or nil when the breaker is disabled.
+func newCircuitBreaker(policy config.CircuitBreakerPolicy) *circuitBreaker {
+	if policy.FailureThreshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{policy: policy}
+}
+
+// allow returns ErrCircuitOpen while the circuit is open; after OpenTimeout a single probe is let through.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.policy.FailureThreshold {
+		return nil
+	}
+	if b.probing || time.Since(b.openedAt) < b.policy.OpenTimeout {
+		return utils.ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request.
+func (b *circuitBreaker) record(err error) {
+	if b == nil || errors.Is(err, utils.ErrCircuitOpen) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !serverFailure(err) {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.policy.FailureThreshold {
+		b.openedAt = time.Now()
+	}
+}
+
+// serverFailure reports whether err indicates an unhealthy server rather than a bad request.
+func serverFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *utils.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	return errors.Is(err, utils.ErrTimeout) || transportError(err)
+}
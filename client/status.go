@@ -1,19 +1,26 @@
 package client
 
 import (
+	"context"
 	"github.com/SamyRai/ollama-go/structures"
 )
 
 // GetVersion retrieves the current API version.
 func (c *OllamaClient) GetVersion() (*structures.VersionResponse, error) {
-	var resp structures.VersionResponse
-	err := c.Request("GET", "/api/version", nil, &resp)
-	return &resp, err
+	return c.GetVersionContext(context.Background())
+}
+
+// GetVersionContext is like GetVersion but cancels the request when ctx is done.
+func (c *OllamaClient) GetVersionContext(ctx context.Context) (*structures.VersionResponse, error) {
+	return roundTrip[structures.VersionResponse](ctx, c, "GET", "/api/version", nil)
 }
 
 // GetRunningProcesses retrieves the list of running structures.
 func (c *OllamaClient) GetRunningProcesses() (*structures.ModelProcessResponse, error) {
-	var resp structures.ModelProcessResponse
-	err := c.Request("GET", "/api/ps", nil, &resp)
-	return &resp, err
+	return c.GetRunningProcessesContext(context.Background())
+}
+
+// GetRunningProcessesContext is like GetRunningProcesses but cancels the request when ctx is done.
+func (c *OllamaClient) GetRunningProcessesContext(ctx context.Context) (*structures.ModelProcessResponse, error) {
+	return roundTrip[structures.ModelProcessResponse](ctx, c, "GET", "/api/ps", nil)
 }
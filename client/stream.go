@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/SamyRai/ollama-go/structures"
+	"iter"
+	"strings"
+)
+
+// errStopStream stops a stream when the consumer of an iterator breaks out of its loop.
+var errStopStream = errors.New("stream stopped by consumer")
+
+// ChatStream streams a chat response chunk by chunk. Breaking out of the loop cancels the request.
+// A failure is yielded once as a non-nil error, after which the sequence ends.
+func (c *OllamaClient) ChatStream(ctx context.Context, req structures.ChatRequest) iter.Seq2[structures.ChatResponse, error] {
+	req.Stream = true
+	return streamSeq[structures.ChatResponse](ctx, c, "/api/chat", req)
+}
+
+// GenerateStream streams a text completion chunk by chunk. Breaking out of the loop cancels the request.
+// A failure is yielded once as a non-nil error, after which the sequence ends.
+func (c *OllamaClient) GenerateStream(ctx context.Context, req structures.CompletionRequest) iter.Seq2[structures.CompletionResponse, error] {
+	req.Stream = true
+	return streamSeq[structures.CompletionResponse](ctx, c, "/api/generate", req)
+}
+
+// streamSeq adapts a streaming call to an iterator.
+func streamSeq[T any](ctx context.Context, c *OllamaClient, endpoint string, req interface{}) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		_, err := streamTrip(ctx, c, "POST", endpoint, req, func(chunk *T) error {
+			if !yield(*chunk, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopStream) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// ChatAggregator merges streamed chat chunks into a single response.
+type ChatAggregator struct {
+	resp    structures.ChatResponse
+	content strings.Builder
+}
+
+// Add merges a chunk into the aggregate.
+func (a *ChatAggregator) Add(chunk structures.ChatResponse) {
+	a.content.WriteString(chunk.Message.Content)
+	toolCalls := append(a.resp.Message.ToolCalls, chunk.Message.ToolCalls...)
+	topLevelToolCalls := append(a.resp.ToolCalls, chunk.ToolCalls...)
+	images := append(a.resp.Message.Images, chunk.Message.Images...)
+	role := a.resp.Message.Role
+
+	// The final chunk carries the stop reason and timing stats; later chunks win.
+	a.resp = chunk
+	if role != "" {
+		a.resp.Message.Role = role
+	}
+	a.resp.Message.ToolCalls = toolCalls
+	a.resp.Message.Images = images
+	a.resp.ToolCalls = topLevelToolCalls
+}
+
+// Response returns the aggregated response with the full concatenated content.
+func (a *ChatAggregator) Response() *structures.ChatResponse {
+	resp := a.resp
+	resp.Message.Content = a.content.String()
+	return &resp
+}
+
+// CompletionAggregator merges streamed completion chunks into a single response.
+type CompletionAggregator struct {
+	resp    structures.CompletionResponse
+	content strings.Builder
+}
+
+// Add merges a chunk into the aggregate.
+func (a *CompletionAggregator) Add(chunk structures.CompletionResponse) {
+	a.content.WriteString(chunk.Response)
+	a.resp = chunk
+}
+
+// Response returns the aggregated response with the full concatenated text.
+func (a *CompletionAggregator) Response() *structures.CompletionResponse {
+	resp := a.resp
+	resp.Response = a.content.String()
+	return &resp
+}
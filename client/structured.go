@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/jsonschema"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+)
+
+// ChatStructured asks the model for a reply matching the JSON schema derived from T (see jsonschema.For),
+// then decodes and validates the reply. If the reply does not match, the model is shown the validation
+// error and asked again, up to maxRetries more times.
+func ChatStructured[T any](ctx context.Context, c *OllamaClient, req structures.ChatRequest, maxRetries int) (*T, error) {
+	schema, err := jsonschema.For[T]()
+	if err != nil {
+		return nil, err
+	}
+	req.Format = schema
+	req.Stream = false
+	req.Messages = append([]structures.Message(nil), req.Messages...)
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.ChatContext(ctx, req, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := decodeStructured[T](schema, resp.Message.Content)
+		if err == nil || attempt >= maxRetries {
+			return result, err
+		}
+
+		req.Messages = append(req.Messages, resp.Message, structures.Message{
+			Role:    "user",
+			Content: repairPrompt(err),
+		})
+	}
+}
+
+// GenerateStructured is the completion counterpart of ChatStructured. On a mismatch the invalid answer
+// and the validation error are appended to the prompt before asking again.
+func GenerateStructured[T any](ctx context.Context, c *OllamaClient, req structures.CompletionRequest, maxRetries int) (*T, error) {
+	schema, err := jsonschema.For[T]()
+	if err != nil {
+		return nil, err
+	}
+	req.Format = schema
+	req.Stream = false
+	prompt := req.Prompt
+
+	for attempt := 0; ; attempt++ {
+		resp, err := c.GenerateCompletionContext(ctx, req, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := decodeStructured[T](schema, resp.Response)
+		if err == nil || attempt >= maxRetries {
+			return result, err
+		}
+
+		req.Prompt = prompt + "\n\nYour previous answer was:\n" + resp.Response + "\n\n" + repairPrompt(err)
+	}
+}
+
+// decodeStructured validates content against schema and decodes it into T.
+func decodeStructured[T any](schema *jsonschema.Schema, content string) (*T, error) {
+	if err := schema.ValidateJSON([]byte(content)); err != nil {
+		return nil, err
+	}
+	result := new(T)
+	if err := json.Unmarshal([]byte(content), result); err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrSchemaValidation, err)
+	}
+	return result, nil
+}
+
+// repairPrompt asks the model to correct a reply that failed validation.
+func repairPrompt(err error) string {
+	return "Your reply did not match the required JSON schema (" + err.Error() + "). " +
+		"Reply again with only a JSON value that matches the schema."
+}
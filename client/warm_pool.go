@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"time"
+)
+
+// Warm pool defaults.
+const (
+	DefaultWarmPoolKeepAlive     = 5 * time.Minute
+	DefaultWarmPoolRefreshBefore = time.Minute
+	DefaultWarmPoolInterval      = 30 * time.Second
+)
+
+// WarmPool keeps a set of models resident by watching the running models (/api/ps) and reloading any that
+// are missing or whose ExpiresAt is about to pass.
+type WarmPool struct {
+	Client        *OllamaClient
+	Models        []string                      // Models to keep loaded.
+	KeepAlive     time.Duration                 // Keep-alive requested on each load (DefaultWarmPoolKeepAlive if zero, forever if negative).
+	RefreshBefore time.Duration                 // Reload when a model expires within this margin (DefaultWarmPoolRefreshBefore if zero).
+	Interval      time.Duration                 // How often Run checks the running models (DefaultWarmPoolInterval if zero).
+	OnLoad        func(model string, err error) // Optional: Called after every load attempt.
+}
+
+// NewWarmPool creates a pool that keeps models loaded with the default timings.
+func NewWarmPool(c *OllamaClient, models ...string) *WarmPool {
+	return &WarmPool{Client: c, Models: models}
+}
+
+// Run syncs the pool immediately and then every Interval until ctx is done, returning ctx's error.
+// Load failures are reported to OnLoad and retried on the next tick.
+func (p *WarmPool) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultWarmPoolInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		_ = p.Sync(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync performs a single check, loading every model that is not running or expires within RefreshBefore.
+// It returns the load errors joined together.
+func (p *WarmPool) Sync(ctx context.Context) error {
+	running, err := p.Client.GetRunningProcessesContext(ctx)
+	if err != nil {
+		return fmt.Errorf("listing running models: %w", err)
+	}
+	expires := map[string]time.Time{}
+	for _, proc := range running.Models {
+		expires[normalizeModelName(proc.Name)] = proc.ExpiresAt
+	}
+
+	refreshBefore := p.RefreshBefore
+	if refreshBefore <= 0 {
+		refreshBefore = DefaultWarmPoolRefreshBefore
+	}
+	keepAlive := p.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = DefaultWarmPoolKeepAlive
+	}
+
+	var errs []error
+	for _, model := range p.Models {
+		expiresAt, ok := expires[normalizeModelName(model)]
+		if ok && time.Until(expiresAt) > refreshBefore {
+			continue
+		}
+		err := p.Client.LoadModel(ctx, model, structures.KeepAliveFor(keepAlive))
+		if p.OnLoad != nil {
+			p.OnLoad(model, err)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("loading %s: %w", model, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,83 @@
+// Command ollama-gateway serves OpenAI's chat completions, completions, embeddings and models APIs in
+// front of an Ollama server, so OpenAI clients and SDKs can use local models by changing their base URL.
+//
+// Usage:
+//
+//	ollama-gateway [--listen :8080] [--host URL] [--api-key KEY] [--alias gpt-4o=llama3.2 ...]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/gateway"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// aliases collects repeated --alias name=model flags.
+type aliases map[string]string
+
+func (a aliases) String() string {
+	return fmt.Sprint(map[string]string(a))
+}
+
+func (a aliases) Set(value string) error {
+	name, model, ok := strings.Cut(value, "=")
+	if !ok || name == "" || model == "" {
+		return fmt.Errorf("want name=model, got %q", value)
+	}
+	a[name] = model
+	return nil
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("ollama-gateway: ")
+
+	cfg, err := config.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if os.Getenv("OLLAMA_TIMEOUT") == "" {
+		cfg.Timeout = 0 // Streamed replies can take minutes; callers bound requests themselves
+	}
+	fs := flag.NewFlagSet("ollama-gateway", flag.ExitOnError)
+	listen := fs.String("listen", ":8080", "`address` to serve the OpenAI API on")
+	host := fs.String("host", cfg.BaseURL, "Ollama server `URL` (env OLLAMA_HOST)")
+	fs.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "per-request timeout to Ollama, 0 for none (env OLLAMA_TIMEOUT)")
+	apiKey := fs.String("api-key", os.Getenv("OLLAMA_GATEWAY_API_KEY"), "require this bearer `token` from callers (env OLLAMA_GATEWAY_API_KEY)")
+	models := aliases{}
+	fs.Var(models, "alias", "serve an Ollama model under another `name=model`; may be repeated")
+	_ = fs.Parse(os.Args[1:])
+	if fs.NArg() > 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cfg.BaseURL = config.HostURL(*host)
+
+	g := gateway.New(client.NewClient(cfg))
+	g.APIKey, g.Aliases = *apiKey, models
+	srv := &http.Server{Addr: *listen, Handler: g, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	log.Printf("serving on %s for %s", *listen, cfg.BaseURL)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,238 @@
+package command
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/SamyRai/ollama-go/session"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/transcript"
+	"github.com/SamyRai/ollama-go/utils"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// replHelp lists the commands understood by the chat REPL.
+const replHelp = `Commands:
+  /system [TEXT]  Show or set the system prompt
+  /history        Show the conversation
+  /usage          Show token usage
+  /clear          Forget the conversation
+  /save FILE      Write the conversation to FILE as Markdown
+  /bye            Exit
+Wrap input in """ to span several lines.
+`
+
+func chatCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	var f requestFlags
+	f.register(fs)
+	system := fs.String("system", "", "system prompt")
+	name := fs.String("session", "", "save the conversation as `name`, resuming it if it exists")
+	dir := fs.String("session-dir", defaultSessionDir(), "`directory` for saved conversations")
+
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usageError("missing model")
+		}
+		s, err := e.openSession(args[0], *name, *dir)
+		if err != nil {
+			return err
+		}
+		if *system != "" {
+			s.System = *system
+		}
+		if !reflect.ValueOf(f.options).IsZero() {
+			s.Options = f.options
+		}
+		if f.keepAlive != nil {
+			s.KeepAlive = f.keepAlive
+		}
+		if err := s.Options.Validate(); err != nil {
+			return usageError("%v", err)
+		}
+		if !e.json && !f.noStream {
+			s.OnChunk = func(chunk structures.ChatResponse) {
+				fmt.Fprint(e.stdout, chunk.Message.Content)
+			}
+		}
+		_ = s.SetBudgetFromModel(e.ctx, 0) // Without a known context window the history is not trimmed
+
+		r := &repl{env: e, session: s, images: f.images}
+		if len(args) > 1 {
+			return r.send(strings.Join(args[1:], " "))
+		}
+		return r.run()
+	}
+}
+
+// defaultSessionDir returns where conversations are saved unless --session-dir is given.
+func defaultSessionDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ollama-go", "sessions")
+}
+
+// openSession resumes the saved session name, or starts a new one (saved under name if it is set).
+func (e *env) openSession(model, name, dir string) (*session.Session, error) {
+	if name == "" {
+		return session.New(e.client, model, ""), nil
+	}
+	if dir == "" {
+		return nil, usageError("--session needs --session-dir")
+	}
+	store, err := session.NewFileStore(dir, session.FormatJSON)
+	if err != nil {
+		return nil, err
+	}
+	s, err := session.Open(e.ctx, e.client, store, name)
+	if errors.Is(err, utils.ErrSessionNotFound) {
+		s = session.New(e.client, model, "")
+		s.ID, s.Store = name, store
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.Model = model
+	return s, nil
+}
+
+// repl is an interactive chat.
+type repl struct {
+	*env
+	session *session.Session
+	images  []string // Attached to the next message only.
+}
+
+// run reads messages and commands from stdin until it ends or /bye. When stdin is not a terminal, each
+// line is a message and the first failed request ends the chat.
+func (r *repl) run() error {
+	tty := interactive(r.stdin)
+	if tty {
+		fmt.Fprintf(r.stdout, "Chatting with %s. Type /help for commands.\n", r.session.Model)
+	}
+	scanner := bufio.NewScanner(r.stdin)
+	scanner.Buffer(nil, 1<<20)
+	for {
+		if tty {
+			fmt.Fprint(r.stdout, ">>> ")
+		}
+		input, ok := readInput(scanner, func() {
+			if tty {
+				fmt.Fprint(r.stdout, "... ")
+			}
+		})
+		if !ok {
+			return scanner.Err()
+		}
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+
+		var err error
+		if strings.HasPrefix(input, "/") {
+			var done bool
+			if done, err = r.command(input); done {
+				return nil
+			}
+		} else {
+			err = r.send(input)
+		}
+		if err != nil && (!tty || r.ctx.Err() != nil) {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(r.stderr, "error: %v\n", err)
+		}
+	}
+}
+
+// readInput reads one message, which spans several lines if it starts with """ and ends at the next """.
+// more is called before each continuation line.
+func readInput(scanner *bufio.Scanner, more func()) (string, bool) {
+	if !scanner.Scan() {
+		return "", false
+	}
+	line := scanner.Text()
+	rest, multiline := strings.CutPrefix(strings.TrimSpace(line), `"""`)
+	if !multiline {
+		return line, true
+	}
+	lines := []string{}
+	for {
+		if text, end := strings.CutSuffix(rest, `"""`); end {
+			return strings.Join(append(lines, text), "\n"), true
+		}
+		lines = append(lines, rest)
+		more()
+		if !scanner.Scan() {
+			return strings.Join(lines, "\n"), true
+		}
+		rest = scanner.Text()
+	}
+}
+
+// send sends a message and prints the reply. A reply is printed even if saving the session then fails.
+func (r *repl) send(content string) error {
+	resp, err := r.session.Send(r.ctx, content, r.images...)
+	if resp == nil {
+		return err
+	}
+	r.images = nil
+	switch {
+	case r.json:
+		if err := r.printLine(resp); err != nil {
+			return err
+		}
+	case r.session.OnChunk != nil:
+		fmt.Fprintln(r.stdout)
+	default:
+		fmt.Fprintln(r.stdout, resp.Message.Content)
+	}
+	return err
+}
+
+// command runs a /command and reports whether the chat should end.
+func (r *repl) command(input string) (bool, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(input), " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "/bye", "/exit", "/quit":
+		return true, nil
+	case "/help", "/?":
+		fmt.Fprint(r.stdout, replHelp)
+	case "/system":
+		if arg == "" {
+			fmt.Fprintln(r.stdout, r.session.System)
+			return false, nil
+		}
+		r.session.System = arg
+	case "/history":
+		for _, msg := range r.session.Record().Messages {
+			fmt.Fprintf(r.stdout, "%s: %s\n", msg.Role, msg.Content)
+		}
+	case "/usage":
+		u := r.session.Usage
+		fmt.Fprintf(r.stdout, "requests: %d\nprompt tokens: %d\ngenerated tokens: %d\ncontext: ~%d tokens\n",
+			u.Requests, u.PromptTokens, u.EvalTokens, r.session.Tokens())
+	case "/clear":
+		r.session.Reset()
+	case "/save":
+		if arg == "" {
+			return false, errors.New("/save needs a file name")
+		}
+		md, err := transcript.ToMarkdown(r.session.Request().Messages)
+		if err != nil {
+			return false, err
+		}
+		return false, os.WriteFile(arg, []byte(md), 0o644)
+	default:
+		return false, fmt.Errorf("unknown command %s (try /help)", name)
+	}
+	return false, nil
+}
@@ -0,0 +1,261 @@
+// Package command implements the ollama-go command-line tool. It lives outside package main so the
+// commands can be run and tested in-process through Run.
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	ollama "github.com/SamyRai/ollama-go"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Exit codes returned by Run.
+const (
+	ExitOK    = 0 // The command succeeded.
+	ExitError = 1 // The command failed (e.g., the server returned an error).
+	ExitUsage = 2 // The command line was invalid.
+)
+
+// errUsage marks errors caused by the command line rather than the server.
+var errUsage = errors.New("usage")
+
+// env is the state shared by every command.
+type env struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	json   bool // Print results as JSON for scripts.
+	client *client.OllamaClient
+}
+
+// command is a subcommand. Flags are declared on fs by setup, which returns the function that runs it
+// with the remaining arguments.
+type command struct {
+	usage   string // Arguments, shown after the command name.
+	summary string
+	setup   func(fs *flag.FlagSet, e *env) func(args []string) error
+}
+
+var commands = map[string]command{
+	"chat":     {"MODEL [MESSAGE...]", "Chat with a model, interactively unless a message is given", chatCommand},
+	"generate": {"MODEL [PROMPT...]", "Generate a completion for a prompt (read from stdin if not given)", generateCommand},
+	"embed":    {"MODEL [TEXT...]", "Print embeddings, one per text or per line of stdin", embedCommand},
+	"list":     {"", "List local models", listCommand},
+	"show":     {"MODEL", "Show information about a model", showCommand},
+	"ps":       {"", "List running models", psCommand},
+	"pull":     {"MODEL", "Download a model from a registry", pullCommand},
+	"push":     {"MODEL", "Upload a model to a registry", pushCommand},
+	"cp":       {"SOURCE DESTINATION", "Copy a model", copyCommand},
+	"rm":       {"MODEL...", "Delete models", removeCommand},
+	"create":   {"MODEL", "Create a model from a Modelfile", createCommand},
+	"version":  {"", "Print the client and server versions", versionCommand},
+}
+
+// Run runs the command line args (without the program name) and returns the exit code.
+// Configuration comes from the environment (see config.FromEnv) and is overridden by flags.
+func Run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		if len(args) > 1 {
+			args = []string{args[1], "-h"}
+		} else {
+			usage(stdout)
+			return ExitOK
+		}
+	}
+	if args[0] == "--version" {
+		args[0] = "version"
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "ollama-go: unknown command %q\n\n", args[0])
+		usage(stderr)
+		return ExitUsage
+	}
+
+	e := &env{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr}
+	fs := flag.NewFlagSet("ollama-go "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ollama-go %s [flags] %s\n\n%s.\n\nFlags:\n", args[0], cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	cfg, err := config.FromEnv()
+	if err != nil {
+		fmt.Fprintf(stderr, "ollama-go: %v\n", err)
+		return ExitUsage
+	}
+	if os.Getenv("OLLAMA_TIMEOUT") == "" {
+		cfg.Timeout = 0 // Replies can take minutes to generate; the library default is meant for services
+	}
+	host := fs.String("host", cfg.BaseURL, "Ollama server `URL` or host:port (env OLLAMA_HOST)")
+	fs.DurationVar(&cfg.Timeout, "timeout", cfg.Timeout, "per-request timeout, 0 for none (env OLLAMA_TIMEOUT)")
+	fs.StringVar(&cfg.APIKey, "api-key", cfg.APIKey, "bearer `token` sent with every request (env OLLAMA_API_KEY)")
+	fs.IntVar(&cfg.Retry.MaxRetries, "retries", cfg.Retry.MaxRetries, "retries for transient failures (env OLLAMA_MAX_RETRIES)")
+	fs.BoolVar(&e.json, "json", false, "print results as JSON")
+	run := cmd.setup(fs, e)
+
+	rest, err := parseInterleaved(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+	if err != nil {
+		return ExitUsage
+	}
+	cfg.BaseURL = config.HostURL(*host)
+	e.client = client.NewClient(cfg)
+
+	if err := run(rest); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(stderr, "ollama-go %s: %v\n", args[0], err)
+			fs.Usage()
+			return ExitUsage
+		}
+		fmt.Fprintf(stderr, "ollama-go %s: %v\n", args[0], err)
+		return ExitError
+	}
+	return ExitOK
+}
+
+// usage prints the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "%s: command-line client for the Ollama API.\n\nUsage: ollama-go COMMAND [flags] [args]\n\nCommands:\n", ollama.Version())
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun 'ollama-go COMMAND -h' for the flags of a command.\n")
+}
+
+// parseInterleaved parses flags that may appear before, between or after positional arguments, which it
+// returns. Everything after "--" is positional.
+func parseInterleaved(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional, tail []string
+	for i, arg := range args {
+		if arg == "--" {
+			args, tail = args[:i], args[i+1:]
+			break
+		}
+	}
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return append(positional, tail...), nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// usageError reports an invalid command line.
+func usageError(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", errUsage, fmt.Sprintf(format, args...))
+}
+
+// exactArgs checks the number of positional arguments.
+func exactArgs(args []string, names ...string) error {
+	if len(args) != len(names) && len(names) == 0 {
+		return usageError("unexpected argument %q", args[0])
+	}
+	if len(args) != len(names) {
+		return usageError("expected %s, got %d argument(s)", strings.Join(names, " and "), len(args))
+	}
+	return nil
+}
+
+// printJSON writes v as indented JSON.
+func (e *env) printJSON(v interface{}) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printLine writes v as a single line of JSON, for streams of results.
+func (e *env) printLine(v interface{}) error {
+	return json.NewEncoder(e.stdout).Encode(v)
+}
+
+// interactive reports whether r is a terminal.
+func interactive(r interface{}) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// humanBytes formats a byte count in decimal units, as the Ollama CLI does.
+func humanBytes(n int64) string {
+	const unit = 1000
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n), 0
+	for value >= unit && exp < 5 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "kMGTP"[exp-1])
+}
+
+// humanTime formats t relative to now (e.g., "3 days ago" or "4 minutes from now").
+func humanTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d, suffix := now.Sub(t), "ago"
+	if d < 0 {
+		d, suffix = -d, "from now"
+	}
+	if d > 100*365*24*time.Hour {
+		return "forever"
+	}
+	var amount int
+	var unit string
+	switch {
+	case d < time.Minute && suffix == "ago":
+		return "just now"
+	case d < time.Minute:
+		return "in a moment"
+	case d < time.Hour:
+		amount, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		amount, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		amount, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		amount, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		amount, unit = int(d/(365*24*time.Hour)), "year"
+	}
+	if amount != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s %s", amount, unit, suffix)
+}
+
+// shortDigest returns the first 12 hex digits of a digest, as model IDs are shown.
+func shortDigest(digest string) string {
+	digest = strings.TrimPrefix(digest, "sha256:")
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
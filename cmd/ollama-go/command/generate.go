@@ -0,0 +1,169 @@
+package command
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// requestFlags are the flags shared by chat and generate.
+type requestFlags struct {
+	options   structures.Options
+	keepAlive *structures.KeepAlive
+	images    []string
+	noStream  bool
+}
+
+func (f *requestFlags) register(fs *flag.FlagSet) {
+	intOption := func(name, usage string, dst **int) {
+		fs.Func(name, usage, func(s string) error {
+			v, err := strconv.Atoi(s)
+			*dst = &v
+			return err
+		})
+	}
+	floatOption := func(name, usage string, dst **float64) {
+		fs.Func(name, usage, func(s string) error {
+			v, err := strconv.ParseFloat(s, 64)
+			*dst = &v
+			return err
+		})
+	}
+	floatOption("temperature", "sampling temperature", &f.options.Temperature)
+	floatOption("top-p", "nucleus sampling threshold", &f.options.TopP)
+	intOption("top-k", "sample from the k most likely tokens", &f.options.TopK)
+	intOption("seed", "random seed for reproducible output", &f.options.Seed)
+	intOption("num-ctx", "context window size in tokens", &f.options.NumCtx)
+	intOption("num-predict", "maximum tokens to generate", &f.options.NumPredict)
+	fs.Func("stop", "stop sequence (repeatable)", func(s string) error {
+		f.options.Stop = append(f.options.Stop, s)
+		return nil
+	})
+	fs.Func("keepalive", "how long to keep the model loaded (e.g., 10m, or forever)", func(s string) error {
+		if s == "forever" {
+			f.keepAlive = structures.Ptr(structures.KeepAliveForever)
+			return nil
+		}
+		d, err := time.ParseDuration(s)
+		f.keepAlive = structures.KeepAliveFor(d)
+		return err
+	})
+	fs.Func("image", "image `file` to attach (repeatable)", func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f.images = append(f.images, base64.StdEncoding.EncodeToString(data))
+		return nil
+	})
+	fs.BoolVar(&f.noStream, "no-stream", false, "wait for the whole reply instead of streaming it")
+}
+
+// readAll returns stdin with the trailing newline removed.
+func readAll(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	return strings.TrimRight(string(data), "\r\n"), err
+}
+
+func generateCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	var f requestFlags
+	f.register(fs)
+	suffix := fs.String("suffix", "", "text that follows the generated text (fill-in-the-middle)")
+	raw := fs.Bool("raw", false, "send the prompt without applying the model's template")
+	var format interface{}
+	fs.Func("format", `response format: "json" or a JSON schema`, func(s string) error {
+		if s == "json" {
+			format = s
+			return nil
+		}
+		if !json.Valid([]byte(s)) {
+			return errors.New(`expected "json" or a JSON schema`)
+		}
+		format = json.RawMessage(s)
+		return nil
+	})
+
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usageError("missing model")
+		}
+		prompt := strings.Join(args[1:], " ")
+		if prompt == "" {
+			var err error
+			if prompt, err = readAll(e.stdin); err != nil {
+				return err
+			}
+		}
+		req := structures.CompletionRequest{
+			Model: args[0], Prompt: prompt, Suffix: *suffix, Images: f.images, Format: format,
+			Options: f.options, Stream: !f.noStream && !e.json, Raw: *raw, KeepAlive: f.keepAlive,
+		}
+		if err := req.Validate(); err != nil {
+			return usageError("%v", err)
+		}
+
+		resp, err := e.client.GenerateCompletionContext(e.ctx, req, func(chunk structures.CompletionResponse) {
+			fmt.Fprint(e.stdout, chunk.Response)
+		})
+		if err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(resp)
+		}
+		if !req.Stream {
+			fmt.Fprint(e.stdout, resp.Response)
+		}
+		fmt.Fprintln(e.stdout)
+		return nil
+	}
+}
+
+func embedCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	truncate := fs.Bool("truncate", true, "truncate inputs longer than the context window instead of failing")
+
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usageError("missing model")
+		}
+		inputs := args[1:]
+		if len(inputs) == 0 {
+			scanner := bufio.NewScanner(e.stdin)
+			scanner.Buffer(nil, 1<<20)
+			for scanner.Scan() {
+				if line := strings.TrimSpace(scanner.Text()); line != "" {
+					inputs = append(inputs, line)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+		}
+		if len(inputs) == 0 {
+			return usageError("nothing to embed")
+		}
+
+		resp, err := e.client.GenerateEmbeddingsContext(e.ctx, structures.EmbeddingRequest{Model: args[0], Input: inputs, Truncate: *truncate})
+		if err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(resp)
+		}
+		for _, embedding := range resp.Embeddings {
+			if err := e.printLine(embedding); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
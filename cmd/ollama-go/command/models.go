@@ -0,0 +1,297 @@
+package command
+
+import (
+	"flag"
+	"fmt"
+	ollama "github.com/SamyRai/ollama-go"
+	"github.com/SamyRai/ollama-go/modelfile"
+	"github.com/SamyRai/ollama-go/structures"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// table writes aligned columns to stdout.
+func (e *env) table(header ...string) *tabwriter.Writer {
+	w := tabwriter.NewWriter(e.stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	return w
+}
+
+func listCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	return func(args []string) error {
+		if err := exactArgs(args); err != nil {
+			return err
+		}
+		resp, err := e.client.ListModelsContext(e.ctx)
+		if err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(resp)
+		}
+		w, now := e.table("NAME", "ID", "SIZE", "MODIFIED"), time.Now()
+		for _, m := range resp.Models {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Name, shortDigest(m.Digest), humanBytes(m.Size), humanTime(m.ModifiedAt, now))
+		}
+		return w.Flush()
+	}
+}
+
+func psCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	return func(args []string) error {
+		if err := exactArgs(args); err != nil {
+			return err
+		}
+		resp, err := e.client.GetRunningProcessesContext(e.ctx)
+		if err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(resp)
+		}
+		w, now := e.table("NAME", "ID", "SIZE", "PROCESSOR", "UNTIL"), time.Now()
+		for _, m := range resp.Models {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Name, shortDigest(m.Digest), humanBytes(m.Size), processor(m), humanTime(m.ExpiresAt, now))
+		}
+		return w.Flush()
+	}
+}
+
+// processor describes how a running model is split between CPU and GPU memory.
+func processor(m structures.ModelProcess) string {
+	switch {
+	case m.Size <= 0 || m.VRAMSize <= 0:
+		return "100% CPU"
+	case m.VRAMSize >= m.Size:
+		return "100% GPU"
+	}
+	gpu := int(float64(m.VRAMSize) / float64(m.Size) * 100)
+	return fmt.Sprintf("%d%%/%d%% CPU/GPU", 100-gpu, gpu)
+}
+
+func showCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	verbose := fs.Bool("verbose", false, "include tensors and full metadata (with --json)")
+	var field string
+	for _, name := range []string{"modelfile", "parameters", "template", "system", "license"} {
+		fs.BoolFunc(name, "print only the model's "+name, func(string) error {
+			field = name
+			return nil
+		})
+	}
+
+	return func(args []string) error {
+		if err := exactArgs(args, "MODEL"); err != nil {
+			return err
+		}
+		resp, err := e.client.ShowModelContext(e.ctx, structures.ShowModelRequest{Model: args[0], Verbose: *verbose})
+		if err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(resp)
+		}
+		switch field {
+		case "modelfile":
+			fmt.Fprintln(e.stdout, resp.Modelfile)
+		case "parameters":
+			fmt.Fprintln(e.stdout, resp.Parameters)
+		case "template":
+			fmt.Fprintln(e.stdout, resp.Template)
+		case "system":
+			fmt.Fprintln(e.stdout, resp.System)
+		case "license":
+			fmt.Fprintln(e.stdout, resp.License)
+		default:
+			return e.printModel(resp)
+		}
+		return nil
+	}
+}
+
+// printModel prints a summary of a model's details, capabilities, parameters, system prompt and license.
+func (e *env) printModel(resp *structures.ShowModelResponse) error {
+	w := tabwriter.NewWriter(e.stdout, 0, 0, 3, ' ', 0)
+	section := func(title string) {
+		fmt.Fprintf(w, "  %s\n", title)
+	}
+	row := func(key string, value interface{}) {
+		if value != "" && value != int64(0) {
+			fmt.Fprintf(w, "    %s\t%v\n", key, value)
+		}
+	}
+
+	section("Model")
+	row("architecture", resp.ModelInfo.Architecture())
+	row("parameters", resp.Details.ParameterSize)
+	row("context length", resp.ModelInfo.ContextLength())
+	row("embedding length", resp.ModelInfo.EmbeddingLength())
+	row("quantization", resp.Details.Quantization)
+	if len(resp.Capabilities) > 0 {
+		fmt.Fprintln(w)
+		section("Capabilities")
+		for _, capability := range resp.Capabilities {
+			fmt.Fprintf(w, "    %s\n", capability)
+		}
+	}
+	if resp.Parameters != "" {
+		fmt.Fprintln(w)
+		section("Parameters")
+		for _, line := range strings.Split(strings.TrimSpace(resp.Parameters), "\n") {
+			key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+			row(key, strings.TrimSpace(value))
+		}
+	}
+	if resp.System != "" {
+		fmt.Fprintln(w)
+		section("System")
+		fmt.Fprintf(w, "    %s\n", firstLine(resp.System))
+	}
+	if resp.License != "" {
+		fmt.Fprintln(w)
+		section("License")
+		fmt.Fprintf(w, "    %s\n", firstLine(resp.License))
+	}
+	return w.Flush()
+}
+
+// firstLine returns the first non-blank line of s, marking any that follow with an ellipsis.
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if line, _, more := strings.Cut(s, "\n"); more {
+		return strings.TrimSpace(line) + " ..."
+	}
+	return s
+}
+
+func pullCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	insecure := fs.Bool("insecure", false, "allow insecure connections to the registry")
+	return func(args []string) error {
+		if err := exactArgs(args, "MODEL"); err != nil {
+			return err
+		}
+		p := e.progressBar()
+		defer p.finish()
+		_, err := e.client.PullModelProgress(e.ctx, structures.PullRequest{Model: args[0], Insecure: *insecure}, p.update)
+		return err
+	}
+}
+
+func pushCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	insecure := fs.Bool("insecure", false, "allow insecure connections to the registry")
+	return func(args []string) error {
+		if err := exactArgs(args, "MODEL"); err != nil {
+			return err
+		}
+		p := e.progressBar()
+		defer p.finish()
+		_, err := e.client.PushModelProgress(e.ctx, structures.PushRequest{Model: args[0], Insecure: *insecure}, p.update)
+		return err
+	}
+}
+
+func copyCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	return func(args []string) error {
+		if err := exactArgs(args, "SOURCE", "DESTINATION"); err != nil {
+			return err
+		}
+		if err := e.client.CopyModelContext(e.ctx, args[0], args[1]); err != nil {
+			return err
+		}
+		if e.json {
+			return e.printJSON(structures.CopyRequest{Source: args[0], Destination: args[1]})
+		}
+		fmt.Fprintf(e.stdout, "copied '%s' to '%s'\n", args[0], args[1])
+		return nil
+	}
+}
+
+func removeCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			return usageError("missing model")
+		}
+		deleted := []string{}
+		for _, model := range args {
+			if err := e.client.DeleteModelContext(e.ctx, model); err != nil {
+				return fmt.Errorf("deleting %s: %w", model, err)
+			}
+			deleted = append(deleted, model)
+			if !e.json {
+				fmt.Fprintf(e.stdout, "deleted '%s'\n", model)
+			}
+		}
+		if e.json {
+			return e.printJSON(map[string][]string{"deleted": deleted})
+		}
+		return nil
+	}
+}
+
+func createCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	file := fs.String("f", "Modelfile", "`path` of the Modelfile")
+	quantize := fs.String("quantize", "", "quantize the model to this `type` (e.g., q4_K_M)")
+
+	return func(args []string) error {
+		if err := exactArgs(args, "MODEL"); err != nil {
+			return err
+		}
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		mf, err := modelfile.Parse(f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+
+		p := e.progressBar()
+		defer p.finish()
+		req, err := mf.CreateRequest(args[0], func(path string) (string, error) {
+			return e.client.UploadBlob(e.ctx, modelfilePath(*file, path), p.update)
+		})
+		if err != nil {
+			return err
+		}
+		req.Quantize = *quantize
+		_, err = e.client.CreateModelProgress(e.ctx, req, p.update)
+		return err
+	}
+}
+
+// modelfilePath resolves a FROM or ADAPTER path, which is relative to the Modelfile's directory.
+func modelfilePath(file, path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(file), path)
+}
+
+func versionCommand(fs *flag.FlagSet, e *env) func(args []string) error {
+	return func(args []string) error {
+		if err := exactArgs(args); err != nil {
+			return err
+		}
+		resp, err := e.client.GetVersionContext(e.ctx)
+		if e.json {
+			if err != nil {
+				return err
+			}
+			return e.printJSON(map[string]string{"client": ollama.Version(), "server": resp.Version})
+		}
+		fmt.Fprintf(e.stdout, "client version: %s\n", ollama.Version())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "server version: %s\n", resp.Version)
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package command
+
+import (
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"io"
+	"strings"
+)
+
+// barWidth is the number of cells in a progress bar.
+const barWidth = 25
+
+// progressEvent is the JSON form of a progress update.
+type progressEvent struct {
+	Status    string  `json:"status"`
+	Digest    string  `json:"digest,omitempty"`
+	Total     int64   `json:"total,omitempty"`
+	Completed int64   `json:"completed,omitempty"`
+	Percent   float64 `json:"percent"`
+}
+
+// progressBar shows pull, push and create progress on stderr: one line per status and, on a terminal, a bar
+// redrawn in place for each layer being transferred. With --json every update is printed to stdout instead.
+type progressBar struct {
+	env    *env
+	tty    bool
+	status string // Last status printed.
+	drawn  bool   // A bar is on the current line.
+}
+
+func (e *env) progressBar() *progressBar {
+	return &progressBar{env: e, tty: interactive(e.stderr)}
+}
+
+// update reports an event.
+func (p *progressBar) update(ev client.ProgressEvent) {
+	if p.env.json {
+		line := progressEvent{Status: ev.Status, Percent: ev.Percent}
+		if ev.Layer != nil {
+			line.Digest, line.Total, line.Completed = ev.Layer.Digest, ev.Layer.Total, ev.Layer.Completed
+		}
+		_ = p.env.printLine(line)
+		return
+	}
+
+	w := p.env.stderr
+	if ev.Layer != nil && ev.Layer.Total > 0 && p.tty {
+		fmt.Fprintf(w, "\r%s %s %3.0f%% %s/%s", ev.Status, bar(ev.Layer.Percent), ev.Layer.Percent,
+			humanBytes(ev.Layer.Completed), humanBytes(ev.Layer.Total))
+		if ev.BytesPerSecond > 0 {
+			fmt.Fprintf(w, " %s/s", humanBytes(int64(ev.BytesPerSecond)))
+		}
+		io.WriteString(w, "\033[K") // Clear what is left of a longer previous line
+		p.drawn, p.status = true, ev.Status
+		return
+	}
+	if ev.Status == p.status {
+		return
+	}
+	p.finish()
+	fmt.Fprintln(w, ev.Status)
+	p.status = ev.Status
+}
+
+// finish ends a bar left on the current line.
+func (p *progressBar) finish() {
+	if p.drawn {
+		fmt.Fprintln(p.env.stderr)
+		p.drawn = false
+	}
+}
+
+// bar draws percent (0-100) as a fixed-width bar.
+func bar(percent float64) string {
+	filled := int(percent / 100 * barWidth)
+	if filled > barWidth {
+		filled = barWidth
+	}
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled) + "]"
+}
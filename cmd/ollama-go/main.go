@@ -0,0 +1,17 @@
+// Command ollama-go is a command-line client for the Ollama API built on the ollama-go client package.
+// Run "ollama-go help" for the list of commands.
+package main
+
+import (
+	"context"
+	"github.com/SamyRai/ollama-go/cmd/ollama-go/command"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := command.Run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
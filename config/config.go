@@ -1,15 +1,53 @@
 package config
 
 import (
+    "fmt"
+    "net/http"
     "os"
+    "strconv"
+    "strings"
     "time"
 )
 
 // Config holds the client configuration settings.
 type Config struct {
-    BaseURL    string        // API Base URL
-    Timeout    time.Duration // Request timeout duration
-    APIKey     string        // Authentication key (if required in the future)
+    BaseURL        string               // API Base URL
+    Timeout        time.Duration        // Request timeout duration
+    APIKey         string               // Bearer token sent with every request (if set)
+    Retry          RetryPolicy          // Retry policy for failed requests
+    CircuitBreaker CircuitBreakerPolicy // Fail-fast policy while the server is unhealthy
+}
+
+// String implements fmt.Stringer with the API key redacted, so configs can be logged safely.
+func (c Config) String() string {
+    apiKey := ""
+    if c.APIKey != "" {
+        apiKey = "[REDACTED]"
+    }
+    return fmt.Sprintf("{BaseURL:%s Timeout:%s APIKey:%s Retry:%+v CircuitBreaker:%+v}",
+        c.BaseURL, c.Timeout, apiKey, c.Retry, c.CircuitBreaker)
+}
+
+// GoString keeps the API key out of %#v output.
+func (c Config) GoString() string {
+    return c.String()
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+    MaxRetries           int           // Retries after the first attempt (0 disables retries)
+    InitialBackoff       time.Duration // Delay before the first retry
+    MaxBackoff           time.Duration // Upper bound for any single delay
+    Multiplier           float64       // Backoff growth factor per attempt
+    Jitter               float64       // Random fraction (0-1) added to or removed from each delay
+    RetryableStatusCodes []int         // HTTP statuses worth retrying
+    RetryNonIdempotent   bool          // Also retry creating, copying and deleting models, which may have taken effect
+}
+
+// CircuitBreakerPolicy controls when the client stops calling an unhealthy server.
+type CircuitBreakerPolicy struct {
+    FailureThreshold int           // Consecutive failures that open the circuit (0 disables the breaker)
+    OpenTimeout      time.Duration // How long the circuit stays open before a probe request is allowed
 }
 
 // DefaultConfig returns a default configuration.
@@ -18,5 +56,64 @@ func DefaultConfig() *Config {
         BaseURL: "http://localhost:11434",
         Timeout: 30 * time.Second, // 30-second timeout for API requests
         APIKey:  os.Getenv("OLLAMA_API_KEY"), // Load API Key from environment variable (if needed)
+        Retry:   DefaultRetryPolicy(),
+    }
+}
+
+// DefaultRetryPolicy returns a retry policy suited to a local server that may still be loading a model.
+// Retries are opt-in: its MaxRetries is 0, so set it (or OLLAMA_MAX_RETRIES) to enable them.
+func DefaultRetryPolicy() RetryPolicy {
+    return RetryPolicy{
+        MaxRetries:     0,
+        InitialBackoff: 250 * time.Millisecond,
+        MaxBackoff:     5 * time.Second,
+        Multiplier:     2,
+        Jitter:         0.2,
+        RetryableStatusCodes: []int{
+            http.StatusTooManyRequests,
+            http.StatusBadGateway,
+            http.StatusServiceUnavailable,
+            http.StatusGatewayTimeout,
+        },
+    }
+}
+
+// FromEnv returns the default configuration overridden by the environment:
+// OLLAMA_HOST (URL or host:port), OLLAMA_API_KEY, OLLAMA_TIMEOUT (e.g., "2m") and OLLAMA_MAX_RETRIES.
+func FromEnv() (*Config, error) {
+    cfg := DefaultConfig()
+    if host := os.Getenv("OLLAMA_HOST"); host != "" {
+        cfg.BaseURL = HostURL(host)
+    }
+    if timeout := os.Getenv("OLLAMA_TIMEOUT"); timeout != "" {
+        d, err := time.ParseDuration(timeout)
+        if err != nil {
+            return nil, fmt.Errorf("invalid OLLAMA_TIMEOUT %q: %w", timeout, err)
+        }
+        cfg.Timeout = d
+    }
+    if retries := os.Getenv("OLLAMA_MAX_RETRIES"); retries != "" {
+        n, err := strconv.Atoi(retries)
+        if err != nil || n < 0 {
+            return nil, fmt.Errorf("invalid OLLAMA_MAX_RETRIES %q", retries)
+        }
+        cfg.Retry.MaxRetries = n
+    }
+    return cfg, nil
+}
+
+// HostURL turns an OLLAMA_HOST style value into a base URL. A bare "host" or "host:port" is taken as
+// plain HTTP on localhost or port 11434 where either is missing; full URLs are used as they are.
+func HostURL(host string) string {
+    host = strings.TrimRight(host, "/")
+    if strings.Contains(host, "://") {
+        return host
+    }
+    if !strings.Contains(host, ":") {
+        host += ":11434"
+    }
+    if strings.HasPrefix(host, ":") {
+        host = "127.0.0.1" + host
     }
+    return "http://" + host
 }
@@ -0,0 +1,318 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kind classifies a contract problem.
+type Kind string
+
+// Problem kinds.
+const (
+	MissingEndpoint Kind = "missing endpoint"   // The client calls an endpoint the spec does not describe.
+	UnusedEndpoint  Kind = "unused endpoint"    // The spec describes an endpoint the client does not call.
+	Undocumented    Kind = "undocumented field" // A field the client sends or decodes that the spec does not describe.
+	MissingField    Kind = "missing field"      // A documented field the Go type lacks.
+	TypeMismatch    Kind = "type mismatch"      // The Go type or JSON value does not match the documented type.
+	InvalidSpec     Kind = "invalid spec"       // The spec cannot be followed (e.g., an undefined reference).
+)
+
+// Problem is a single difference between the client and the spec.
+type Problem struct {
+	Endpoint string // Method and path template (e.g., "POST /api/chat").
+	Field    string // JSON path of the field (e.g., "request.messages[].role"); empty for endpoint problems.
+	Kind     Kind
+	Detail   string
+}
+
+func (p Problem) String() string {
+	where := p.Endpoint
+	if p.Field != "" {
+		where += " " + p.Field
+	}
+	return fmt.Sprintf("%s: %s: %s", where, p.Kind, p.Detail)
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// methods are the path item keys that are operations.
+var methods = map[string]bool{"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true, "trace": true}
+
+// Check compares endpoints with the spec: every endpoint must be documented, every documented endpoint used,
+// and the fields of the request and response types must match the documented schemas in name and type.
+// Problems are sorted by endpoint and field.
+func Check(spec *Spec, endpoints []Endpoint) []Problem {
+	c := &checker{spec: spec, seen: map[visit]bool{}}
+	used := map[string]bool{}
+	for _, e := range endpoints {
+		c.endpoint = e.String()
+		used[strings.ToLower(e.Method)+" "+e.Path] = true
+		op := spec.Paths[e.Path][strings.ToLower(e.Method)]
+		if op == nil {
+			c.add("", MissingEndpoint, "not described by the spec")
+			continue
+		}
+		if e.Request != nil {
+			if schema := op.RequestBody.jsonSchema(); schema == nil {
+				c.add("request", MissingField, "no JSON request body is documented")
+			} else {
+				c.compareType("request", e.Request, schema)
+			}
+		}
+		if e.Response != nil {
+			if schema := op.success().jsonSchema(); schema == nil {
+				c.add("response", MissingField, "no JSON success response is documented")
+			} else {
+				c.compareType("response", e.Response, schema)
+			}
+		}
+	}
+
+	for path, ops := range spec.Paths {
+		for method := range ops {
+			if methods[method] && !used[method+" "+path] {
+				c.endpoint = strings.ToUpper(method) + " " + path
+				c.add("", UnusedEndpoint, "not called by the client")
+			}
+		}
+	}
+	return c.sorted()
+}
+
+// CheckPayload checks a JSON request body sent to method and path (a concrete path, e.g. as recorded by a
+// test server) against the documented request schema. Unknown keys and values of the wrong type are
+// reported; absent keys are not, since most fields are optional.
+func CheckPayload(spec *Spec, method, path string, body []byte) []Problem {
+	op, template := spec.Operation(method, path)
+	c := &checker{spec: spec, endpoint: strings.ToUpper(method) + " " + template}
+	if op == nil {
+		c.endpoint = strings.ToUpper(method) + " " + path
+		c.add("", MissingEndpoint, "not described by the spec")
+		return c.problems
+	}
+	schema := op.RequestBody.jsonSchema()
+	if len(strings.TrimSpace(string(body))) == 0 || schema == nil {
+		return nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		c.add("request", TypeMismatch, "body is not JSON: %v", err)
+		return c.problems
+	}
+	c.compareValue("request", v, schema)
+	return c.sorted()
+}
+
+type checker struct {
+	spec     *Spec
+	endpoint string
+	problems []Problem
+	seen     map[visit]bool
+}
+
+// visit identifies a type already compared with a schema, to stop at recursive types.
+type visit struct {
+	t      reflect.Type
+	schema *Schema
+}
+
+func (c *checker) add(field string, kind Kind, format string, args ...interface{}) {
+	c.problems = append(c.problems, Problem{Endpoint: c.endpoint, Field: field, Kind: kind, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) sorted() []Problem {
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		if c.problems[i].Endpoint != c.problems[j].Endpoint {
+			return c.problems[i].Endpoint < c.problems[j].Endpoint
+		}
+		return c.problems[i].Field < c.problems[j].Field
+	})
+	return c.problems
+}
+
+// schemaFor resolves references, returning nil for schemas that accept any value.
+func (c *checker) schemaFor(where string, schema *Schema) *Schema {
+	schema, err := c.spec.resolve(schema)
+	if err != nil {
+		c.add(where, InvalidSpec, "%v", err)
+		return nil
+	}
+	if schema == nil || len(schema.OneOf) > 0 || len(schema.AnyOf) > 0 || schema.Type == "" && schema.Properties == nil {
+		return nil
+	}
+	return schema
+}
+
+// compareType checks a Go type against a schema.
+func (c *checker) compareType(where string, t reflect.Type, schema *Schema) {
+	if schema = c.schemaFor(where, schema); schema == nil {
+		return
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	want := jsonType(t)
+	if want == "" {
+		return // Custom encoding or interface{}: any documented type is accepted
+	}
+	if schema.Type != want {
+		c.add(where, TypeMismatch, "Go type %s encodes as %s, the spec says %s", t, want, schema.Type)
+		return
+	}
+
+	switch {
+	case want == "array":
+		c.compareType(where+"[]", t.Elem(), schema.Items)
+	case t.Kind() == reflect.Map:
+		if schema.AdditionalProperties != nil {
+			c.compareType(where+"{}", t.Elem(), schema.AdditionalProperties.Schema)
+		}
+	case t.Kind() == reflect.Struct:
+		if len(schema.Properties) == 0 && schema.AdditionalProperties != nil && schema.AdditionalProperties.Allowed {
+			return // Free-form object
+		}
+		key := visit{t, schema}
+		if c.seen[key] {
+			return
+		}
+		c.seen[key] = true
+
+		fields := jsonFields(t)
+		for _, name := range sortedKeys(fields) {
+			if prop, ok := schema.Properties[name]; ok {
+				c.compareType(where+"."+name, fields[name], prop)
+			} else {
+				c.add(where+"."+name, Undocumented, "%s has the field but the spec does not describe it", t)
+			}
+		}
+		for _, name := range sortedKeys(schema.Properties) {
+			if _, ok := fields[name]; !ok {
+				c.add(where+"."+name, MissingField, "documented but %s has no such field", t)
+			}
+		}
+	}
+}
+
+// compareValue checks a decoded JSON value against a schema.
+func (c *checker) compareValue(where string, v interface{}, schema *Schema) {
+	if schema = c.schemaFor(where, schema); schema == nil || v == nil {
+		return
+	}
+	got := ""
+	switch v := v.(type) {
+	case map[string]interface{}:
+		got = "object"
+		if schema.Type != got {
+			break
+		}
+		for _, key := range sortedKeys(v) {
+			switch prop, ok := schema.Properties[key]; {
+			case ok:
+				c.compareValue(where+"."+key, v[key], prop)
+			case schema.AdditionalProperties != nil && schema.AdditionalProperties.Allowed:
+				c.compareValue(where+"{}", v[key], schema.AdditionalProperties.Schema)
+			case len(schema.Properties) > 0:
+				c.add(where+"."+key, Undocumented, "sent but the spec does not describe it")
+			}
+		}
+		return
+	case []interface{}:
+		got = "array"
+		if schema.Type == got {
+			for _, item := range v {
+				c.compareValue(where+"[]", item, schema.Items)
+			}
+			return
+		}
+	case string:
+		got = "string"
+	case bool:
+		got = "boolean"
+	case float64:
+		got = "number"
+		if schema.Type == "integer" && v == math.Trunc(v) {
+			return
+		}
+	}
+	if schema.Type != got {
+		c.add(where, TypeMismatch, "sent %s, the spec says %s", got, schema.Type)
+	}
+}
+
+// jsonType returns the JSON type a Go type encodes as, or "" if it cannot be told from the type.
+func jsonType(t reflect.Type) string {
+	if t == timeType {
+		return "string"
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // Base64
+		}
+		return "array"
+	case reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// jsonFields returns the JSON fields of a struct type by name, following encoding/json's rules for tags
+// and embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for embedded, ft := range jsonFields(f.Type) {
+				if _, ok := fields[embedded]; !ok {
+					fields[embedded] = ft
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,43 @@
+package contract
+
+import (
+	"github.com/SamyRai/ollama-go/structures"
+	"reflect"
+)
+
+// Endpoint is an API call made by the client.
+type Endpoint struct {
+	Method   string       // HTTP method.
+	Path     string       // Path template (e.g., "/api/blobs/{digest}").
+	Request  reflect.Type // Type of the JSON request body; nil if there is none.
+	Response reflect.Type // Type of the JSON response, or of each streamed chunk; nil if there is none.
+}
+
+// String returns the method and path.
+func (e Endpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// ClientEndpoints lists every endpoint the client calls, with the structures it sends and receives.
+func ClientEndpoints() []Endpoint {
+	return []Endpoint{
+		{"POST", "/api/generate", typeOf[structures.CompletionRequest](), typeOf[structures.CompletionResponse]()},
+		{"POST", "/api/chat", typeOf[structures.ChatRequest](), typeOf[structures.ChatResponse]()},
+		{"POST", "/api/embed", typeOf[structures.EmbeddingRequest](), typeOf[structures.EmbeddingResponse]()},
+		{"GET", "/api/tags", nil, typeOf[structures.ModelListResponse]()},
+		{"POST", "/api/show", typeOf[structures.ShowModelRequest](), typeOf[structures.ShowModelResponse]()},
+		{"POST", "/api/create", typeOf[structures.ModelManagementRequest](), typeOf[structures.ProgressResponse]()},
+		{"POST", "/api/copy", typeOf[structures.CopyRequest](), nil},
+		{"DELETE", "/api/delete", typeOf[structures.DeleteRequest](), nil},
+		{"POST", "/api/pull", typeOf[structures.PullRequest](), typeOf[structures.ProgressResponse]()},
+		{"POST", "/api/push", typeOf[structures.PushRequest](), typeOf[structures.ProgressResponse]()},
+		{"HEAD", "/api/blobs/{digest}", nil, nil},
+		{"POST", "/api/blobs/{digest}", nil, nil},
+		{"GET", "/api/ps", nil, typeOf[structures.ModelProcessResponse]()},
+		{"GET", "/api/version", nil, typeOf[structures.VersionResponse]()},
+	}
+}
@@ -0,0 +1,143 @@
+// Package contract checks the Go types in the structures package against the OpenAPI document describing
+// the Ollama API (swagger.yml), so that drift between the client and the spec is caught by go test.
+package contract
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+)
+
+// Spec is the part of an OpenAPI 3 document the checks need.
+type Spec struct {
+	Paths      map[string]map[string]*Operation `yaml:"paths"` // Path template to lower-case HTTP method.
+	Components struct {
+		Schemas map[string]*Schema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+// Operation is a single method on a path.
+type Operation struct {
+	OperationID string           `yaml:"operationId"`
+	RequestBody *Body            `yaml:"requestBody"`
+	Responses   map[string]*Body `yaml:"responses"` // Status code to response.
+}
+
+// Body is a request or response body.
+type Body struct {
+	Content map[string]struct {
+		Schema *Schema `yaml:"schema"`
+	} `yaml:"content"` // Media type to schema.
+}
+
+// Schema is the subset of an OpenAPI schema object the checks understand.
+type Schema struct {
+	Ref                  string             `yaml:"$ref"`
+	Type                 string             `yaml:"type"`
+	Format               string             `yaml:"format"`
+	Properties           map[string]*Schema `yaml:"properties"`
+	Items                *Schema            `yaml:"items"`
+	AdditionalProperties *Additional        `yaml:"additionalProperties"`
+	OneOf                []*Schema          `yaml:"oneOf"`
+	AnyOf                []*Schema          `yaml:"anyOf"`
+}
+
+// Additional is the value of additionalProperties: either a boolean or a schema for map values.
+type Additional struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+// UnmarshalYAML accepts a boolean or a schema.
+func (a *Additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// Load reads an OpenAPI document from a YAML or JSON file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes an OpenAPI document in YAML or JSON.
+func Parse(data []byte) (*Spec, error) {
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	return spec, nil
+}
+
+// Operation returns the operation for method on path, where path may be a concrete path such as
+// "/api/blobs/sha256:..." that matches a template like "/api/blobs/{digest}". It also returns the template.
+func (s *Spec) Operation(method, path string) (*Operation, string) {
+	method = strings.ToLower(method)
+	if op := s.Paths[path][method]; op != nil {
+		return op, path
+	}
+	for template, ops := range s.Paths {
+		if op := ops[method]; op != nil && matchPath(template, path) {
+			return op, template
+		}
+	}
+	return nil, ""
+}
+
+// resolve follows a "#/components/schemas/..." reference.
+func (s *Spec) resolve(schema *Schema) (*Schema, error) {
+	for seen := 0; schema != nil && schema.Ref != ""; seen++ {
+		name, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/")
+		if !ok || seen > 32 {
+			return nil, fmt.Errorf("unsupported reference %q", schema.Ref)
+		}
+		if schema = s.Components.Schemas[name]; schema == nil {
+			return nil, fmt.Errorf("undefined schema %q", name)
+		}
+	}
+	return schema, nil
+}
+
+// jsonSchema returns the application/json schema of a body, if any.
+func (b *Body) jsonSchema() *Schema {
+	if b == nil {
+		return nil
+	}
+	for mediaType, content := range b.Content {
+		if strings.HasPrefix(mediaType, "application/json") || mediaType == "application/x-ndjson" {
+			return content.Schema
+		}
+	}
+	return nil
+}
+
+// success returns the operation's first 2xx response.
+func (o *Operation) success() *Body {
+	for _, code := range []string{"200", "201", "204"} {
+		if body, ok := o.Responses[code]; ok {
+			return body
+		}
+	}
+	return nil
+}
+
+// matchPath reports whether path fits template, where {name} matches a single segment.
+func matchPath(template, path string) bool {
+	want, got := strings.Split(template, "/"), strings.Split(path, "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if want[i] != got[i] && !(strings.HasPrefix(want[i], "{") && strings.HasSuffix(want[i], "}") && got[i] != "") {
+			return false
+		}
+	}
+	return true
+}
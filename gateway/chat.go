@@ -0,0 +1,307 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/jsonschema"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/transcript"
+	"net/http"
+)
+
+// eventStream writes server-sent events. The response headers are sent with the first event, so an error
+// that occurs before any output can still be reported with a proper status.
+type eventStream struct {
+	w       http.ResponseWriter
+	started bool
+}
+
+// send writes v as a data event.
+func (s *eventStream) send(v interface{}) {
+	if !s.started {
+		s.w.Header().Set("Content-Type", "text/event-stream")
+		s.w.Header().Set("Cache-Control", "no-cache")
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(s.w, "data: %s\n\n", data)
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// fail reports err: as an error response if nothing was sent yet, otherwise as a final error event.
+func (s *eventStream) fail(err error) {
+	if !s.started {
+		writeUpstreamError(s.w, err)
+		return
+	}
+	_, resp := upstreamError(err)
+	s.send(resp)
+}
+
+// done ends the stream.
+func (s *eventStream) done() {
+	fmt.Fprint(s.w, "data: [DONE]\n\n")
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (g *Gateway) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req ChatCompletionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	chat, param, err := g.chatRequest(req)
+	if err != nil {
+		badRequest(w, param, "%v", err)
+		return
+	}
+	id, created := newID("chatcmpl-"), g.now().Unix()
+
+	if !req.Stream {
+		resp, err := g.Client.ChatContext(r.Context(), chat, nil)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		msg := toOpenAIMessage(resp.Message)
+		writeJSON(w, http.StatusOK, ChatCompletion{
+			ID: id, Object: "chat.completion", Created: created, Model: req.Model,
+			Choices: []ChatChoice{{Message: msg, FinishReason: finishReason(resp.DoneReason, len(msg.ToolCalls) > 0)}},
+			Usage:   chatUsage(resp),
+		})
+		return
+	}
+
+	stream := &eventStream{w: w}
+	chunk := func(delta ChunkDelta, finish *string) ChatCompletionChunk {
+		return ChatCompletionChunk{
+			ID: id, Object: "chat.completion.chunk", Created: created, Model: req.Model,
+			Choices: []ChunkChoice{{Delta: delta, FinishReason: finish}},
+		}
+	}
+	role, calls := "assistant", 0 // The role is sent with the first delta only
+	resp, err := g.Client.ChatContext(r.Context(), chat, func(c structures.ChatResponse) {
+		delta := ChunkDelta{Content: c.Message.Content}
+		for _, call := range toOpenAIMessage(structures.Message{ToolCalls: c.Message.ToolCalls}).ToolCalls {
+			delta.ToolCalls = append(delta.ToolCalls, ChunkToolCall{Index: calls, OpenAIToolCall: call})
+			calls++
+		}
+		if delta.Content == "" && delta.ToolCalls == nil {
+			return
+		}
+		delta.Role, role = role, ""
+		stream.send(chunk(delta, nil))
+	})
+	if err != nil {
+		stream.fail(err)
+		return
+	}
+	reason := finishReason(resp.DoneReason, calls > 0)
+	stream.send(chunk(ChunkDelta{Role: role}, &reason))
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		final := chunk(ChunkDelta{}, nil)
+		final.Choices, final.Usage = []ChunkChoice{}, chatUsage(resp)
+		stream.send(final)
+	}
+	stream.done()
+}
+
+// chatRequest converts a chat completion request, returning the offending parameter with any error.
+func (g *Gateway) chatRequest(req ChatCompletionRequest) (structures.ChatRequest, string, error) {
+	if req.N != nil && *req.N != 1 {
+		return structures.ChatRequest{}, "n", fmt.Errorf("only n=1 is supported")
+	}
+	messages, err := transcript.FromOpenAI(req.Messages)
+	if err != nil {
+		return structures.ChatRequest{}, "messages", err
+	}
+	if len(messages) == 0 {
+		return structures.ChatRequest{}, "messages", fmt.Errorf("messages must not be empty")
+	}
+	maxTokens := req.MaxCompletionTokens
+	if maxTokens == nil {
+		maxTokens = req.MaxTokens
+	}
+	chat := structures.ChatRequest{
+		Model:    g.modelName(req.Model),
+		Messages: messages,
+		Options:  options(maxTokens, req.Temperature, req.TopP, req.FrequencyPenalty, req.PresencePenalty, req.Seed, req.Stop),
+		Stream:   req.Stream,
+	}
+
+	if req.ToolChoice != "none" {
+		for _, tool := range req.Tools {
+			if tool.Type != "function" {
+				return chat, "tools", fmt.Errorf("unsupported tool type %q", tool.Type)
+			}
+			params, err := toolSchema(tool.Function.Parameters)
+			if err != nil {
+				return chat, "tools", fmt.Errorf("parameters of %s: %v", tool.Function.Name, err)
+			}
+			chat.Tools = append(chat.Tools, structures.Tool{Type: "function", Function: structures.ToolFunction{
+				Name: tool.Function.Name, Description: tool.Function.Description, Parameters: params,
+			}})
+		}
+	}
+
+	if f := req.ResponseFormat; f != nil {
+		switch f.Type {
+		case "", "text":
+		case "json_object":
+			chat.Format = "json"
+		case "json_schema":
+			if f.JSONSchema == nil || len(f.JSONSchema.Schema) == 0 {
+				return chat, "response_format", fmt.Errorf("json_schema.schema is required")
+			}
+			chat.Format = f.JSONSchema.Schema
+		default:
+			return chat, "response_format", fmt.Errorf("unsupported response_format type %q", f.Type)
+		}
+	}
+	if err := chat.Validate(); err != nil {
+		return chat, "", err
+	}
+	return chat, "", nil
+}
+
+// toolSchema decodes tool parameters. Boolean schemas such as "additionalProperties": false, which strict
+// OpenAI tools always carry, have no equivalent in jsonschema.Schema and are dropped.
+func toolSchema(raw json.RawMessage) (*jsonschema.Schema, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(dropBooleanSchemas(v))
+	if err != nil {
+		return nil, err
+	}
+	schema := &jsonschema.Schema{}
+	return schema, json.Unmarshal(data, schema)
+}
+
+// dropBooleanSchemas removes schema keywords whose value is true or false instead of a schema.
+func dropBooleanSchemas(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(bool); ok && (key == "additionalProperties" || key == "items") {
+				delete(v, key)
+				continue
+			}
+			v[key] = dropBooleanSchemas(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = dropBooleanSchemas(v[i])
+		}
+	}
+	return v
+}
+
+// toOpenAIMessage converts a reply, giving each tool call a unique ID.
+func toOpenAIMessage(msg structures.Message) transcript.OpenAIMessage {
+	msg.Role = "assistant"
+	converted, err := transcript.ToOpenAI([]structures.Message{msg})
+	if err != nil {
+		// Only unencodable arguments fail, which cannot come from decoded JSON
+		return transcript.OpenAIMessage{Role: "assistant", Content: transcript.OpenAIContent{Text: msg.Content}}
+	}
+	out := converted[0]
+	for i := range out.ToolCalls {
+		out.ToolCalls[i].ID = newID("call_")
+	}
+	return out
+}
+
+func chatUsage(resp *structures.ChatResponse) *Usage {
+	return &Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+}
+
+func (g *Gateway) completions(w http.ResponseWriter, r *http.Request) {
+	var req CompletionRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	switch {
+	case len(req.Prompt) > 1:
+		badRequest(w, "prompt", "only a single prompt is supported")
+		return
+	case req.N != nil && *req.N != 1:
+		badRequest(w, "n", "only n=1 is supported")
+		return
+	}
+	generate := structures.CompletionRequest{
+		Model:   g.modelName(req.Model),
+		Suffix:  req.Suffix,
+		Options: options(req.MaxTokens, req.Temperature, req.TopP, req.FrequencyPenalty, req.PresencePenalty, req.Seed, req.Stop),
+		Stream:  req.Stream,
+	}
+	if len(req.Prompt) == 1 {
+		generate.Prompt = req.Prompt[0]
+	}
+	if err := generate.Validate(); err != nil {
+		badRequest(w, "", "%v", err)
+		return
+	}
+	id, created := newID("cmpl-"), g.now().Unix()
+	completion := func(text string, finish *string) Completion {
+		return Completion{
+			ID: id, Object: "text_completion", Created: created, Model: req.Model,
+			Choices: []CompletionChoice{{Text: text, FinishReason: finish}},
+		}
+	}
+
+	if !req.Stream {
+		resp, err := g.Client.GenerateCompletionContext(r.Context(), generate, nil)
+		if err != nil {
+			writeUpstreamError(w, err)
+			return
+		}
+		reason := finishReason(resp.DoneReason, false)
+		out := completion(resp.Response, &reason)
+		out.Usage = completionUsage(resp)
+		writeJSON(w, http.StatusOK, out)
+		return
+	}
+
+	stream := &eventStream{w: w}
+	resp, err := g.Client.GenerateCompletionContext(r.Context(), generate, func(c structures.CompletionResponse) {
+		if c.Response != "" {
+			stream.send(completion(c.Response, nil))
+		}
+	})
+	if err != nil {
+		stream.fail(err)
+		return
+	}
+	reason := finishReason(resp.DoneReason, false)
+	stream.send(completion("", &reason))
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		final := completion("", nil)
+		final.Choices, final.Usage = []CompletionChoice{}, completionUsage(resp)
+		stream.send(final)
+	}
+	stream.done()
+}
+
+func completionUsage(resp *structures.CompletionResponse) *Usage {
+	return &Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+}
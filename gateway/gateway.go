@@ -0,0 +1,261 @@
+// Package gateway serves OpenAI's chat completions, completions, embeddings and models APIs on top of an
+// OllamaClient, so tools that only speak OpenAI's wire format can use an Ollama server.
+package gateway
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodySize caps request bodies, which may carry base64 images.
+const maxBodySize = 32 << 20
+
+// Gateway is an http.Handler serving /v1/chat/completions, /v1/completions, /v1/embeddings and /v1/models.
+type Gateway struct {
+	Client  *client.OllamaClient
+	APIKey  string            // Optional: Bearer token callers must send.
+	Aliases map[string]string // Optional: Model names callers use (e.g., "gpt-4o") to Ollama model names.
+
+	once sync.Once
+	mux  *http.ServeMux
+	now  func() time.Time
+}
+
+// New creates a gateway in front of c.
+func New(c *client.OllamaClient) *Gateway {
+	return &Gateway{Client: c}
+}
+
+// ServeHTTP implements http.Handler.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.once.Do(func() {
+		if g.now == nil {
+			g.now = time.Now
+		}
+		g.mux = http.NewServeMux()
+		g.mux.HandleFunc("POST /v1/chat/completions", g.chatCompletions)
+		g.mux.HandleFunc("POST /v1/completions", g.completions)
+		g.mux.HandleFunc("POST /v1/embeddings", g.embeddings)
+		g.mux.HandleFunc("GET /v1/models", g.models)
+		g.mux.HandleFunc("GET /v1/models/{model...}", g.model)
+		g.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			writeError(w, http.StatusNotFound, "invalid_request_error", "unknown_url",
+				fmt.Sprintf("Unknown request URL: %s %s", r.Method, r.URL.Path))
+		})
+	})
+
+	if g.APIKey != "" {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(g.APIKey)) != 1 {
+			writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "Incorrect API key provided.")
+			return
+		}
+	}
+	g.mux.ServeHTTP(w, r)
+}
+
+// modelName maps a requested model name through Aliases.
+func (g *Gateway) modelName(name string) string {
+	if alias, ok := g.Aliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// decode reads a JSON request body into v, writing an error response if it cannot.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "Invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an OpenAI error. code may be empty.
+func writeError(w http.ResponseWriter, status int, kind, code, message string) {
+	writeJSON(w, status, errorResponse(kind, code, message))
+}
+
+func errorResponse(kind, code, message string) ErrorResponse {
+	resp := ErrorResponse{Error: ErrorDetail{Message: message, Type: kind}}
+	if code != "" {
+		resp.Error.Code = &code
+	}
+	return resp
+}
+
+// badRequest reports an invalid or unsupported parameter.
+func badRequest(w http.ResponseWriter, param, format string, args ...interface{}) {
+	resp := errorResponse("invalid_request_error", "", fmt.Sprintf(format, args...))
+	if param != "" {
+		resp.Error.Param = &param
+	}
+	writeJSON(w, http.StatusBadRequest, resp)
+}
+
+// upstreamError maps an error from the Ollama client to an HTTP status and OpenAI error.
+func upstreamError(err error) (int, ErrorResponse) {
+	var apiErr *utils.APIError
+	message := err.Error()
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		message = apiErr.Message
+	}
+	switch {
+	case errors.Is(err, utils.ErrModelNotFound):
+		return http.StatusNotFound, errorResponse("invalid_request_error", "model_not_found", message)
+	case errors.Is(err, utils.ErrInvalidOptions), errors.Is(err, utils.ErrBadRequest), errors.Is(err, utils.ErrBadTranscript):
+		return http.StatusBadRequest, errorResponse("invalid_request_error", "", message)
+	case errors.Is(err, utils.ErrAuthentication):
+		return http.StatusBadGateway, errorResponse("api_error", "upstream_authentication", message)
+	case errors.Is(err, utils.ErrOverloaded), errors.Is(err, utils.ErrCircuitOpen):
+		return http.StatusServiceUnavailable, errorResponse("server_error", "overloaded", message)
+	case errors.Is(err, utils.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, errorResponse("server_error", "timeout", message)
+	case apiErr != nil:
+		return http.StatusBadGateway, errorResponse("server_error", "", message)
+	}
+	return http.StatusInternalServerError, errorResponse("server_error", "", message)
+}
+
+func writeUpstreamError(w http.ResponseWriter, err error) {
+	status, resp := upstreamError(err)
+	writeJSON(w, status, resp)
+}
+
+// newID returns a random ID with the given prefix (e.g., "chatcmpl-").
+func newID(prefix string) string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return prefix + hex.EncodeToString(b)
+}
+
+// options converts OpenAI sampling parameters to Ollama options.
+func options(maxTokens *int, temperature, topP, frequencyPenalty, presencePenalty *float64, seed *int, stop Strings) structures.Options {
+	return structures.Options{
+		NumPredict:       maxTokens,
+		Temperature:      temperature,
+		TopP:             topP,
+		FrequencyPenalty: frequencyPenalty,
+		PresencePenalty:  presencePenalty,
+		Seed:             seed,
+		Stop:             stop,
+	}
+}
+
+// finishReason maps Ollama's done reason to OpenAI's finish reason.
+func finishReason(doneReason string, toolCalls bool) string {
+	switch {
+	case toolCalls:
+		return "tool_calls"
+	case doneReason == "length":
+		return "length"
+	}
+	return "stop"
+}
+
+func (g *Gateway) models(w http.ResponseWriter, r *http.Request) {
+	resp, err := g.Client.ListModelsContext(r.Context())
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	list := ModelList{Object: "list", Data: []Model{}}
+	for _, m := range resp.Models {
+		list.Data = append(list.Data, toModel(m))
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (g *Gateway) model(w http.ResponseWriter, r *http.Request) {
+	name := g.modelName(r.PathValue("model"))
+	resp, err := g.Client.ListModelsContext(r.Context())
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	for _, m := range resp.Models {
+		if m.Name == name || strings.TrimSuffix(m.Name, ":latest") == name {
+			model := toModel(m)
+			model.ID = r.PathValue("model")
+			writeJSON(w, http.StatusOK, model)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "invalid_request_error", "model_not_found", fmt.Sprintf("The model '%s' does not exist", name))
+}
+
+// toModel describes a local model. Models pulled from a namespace are owned by it, others by "library".
+func toModel(m structures.ModelInfo) Model {
+	owner := "library"
+	if namespace, _, ok := strings.Cut(m.Name, "/"); ok {
+		owner = namespace
+	}
+	return Model{ID: m.Name, Object: "model", Created: m.ModifiedAt.Unix(), OwnedBy: owner}
+}
+
+func (g *Gateway) embeddings(w http.ResponseWriter, r *http.Request) {
+	var req EmbeddingRequest
+	if !decode(w, r, &req) {
+		return
+	}
+	switch {
+	case len(req.Input) == 0:
+		badRequest(w, "input", "input must be a string or a non-empty array of strings")
+		return
+	case req.Dimensions != nil:
+		badRequest(w, "dimensions", "dimensions is not supported")
+		return
+	case req.EncodingFormat != "" && req.EncodingFormat != "float" && req.EncodingFormat != "base64":
+		badRequest(w, "encoding_format", "encoding_format must be float or base64")
+		return
+	}
+
+	resp, err := g.Client.GenerateEmbeddingsContext(r.Context(), structures.EmbeddingRequest{
+		Model: g.modelName(req.Model), Input: req.Input, Truncate: true,
+	})
+	if err != nil {
+		writeUpstreamError(w, err)
+		return
+	}
+	list := EmbeddingList{Object: "list", Data: []Embedding{}, Model: req.Model}
+	for i, vector := range resp.Embeddings {
+		embedding := Embedding{Object: "embedding", Embedding: vector, Index: i}
+		if req.EncodingFormat == "base64" {
+			embedding.Embedding = encodeFloats(vector)
+		}
+		list.Data = append(list.Data, embedding)
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// encodeFloats encodes a vector as base64 little-endian float32s, as OpenAI's encoding_format=base64 does.
+func encodeFloats(vector []float32) string {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,239 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/transcript"
+)
+
+// =========================
+// == Chat Completions API ==
+// =========================
+
+// ChatCompletionRequest is the body of POST /v1/chat/completions.
+type ChatCompletionRequest struct {
+	Model               string                     `json:"model"`
+	Messages            []transcript.OpenAIMessage `json:"messages"`
+	Tools               []Tool                     `json:"tools,omitempty"`
+	ToolChoice          interface{}                `json:"tool_choice,omitempty"` // "none", "auto", "required" or a specific function.
+	ResponseFormat      *ResponseFormat            `json:"response_format,omitempty"`
+	Stream              bool                       `json:"stream,omitempty"`
+	StreamOptions       *StreamOptions             `json:"stream_options,omitempty"`
+	N                   *int                       `json:"n,omitempty"` // Only 1 is supported.
+	MaxTokens           *int                       `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int                       `json:"max_completion_tokens,omitempty"` // Replaces max_tokens.
+	Temperature         *float64                   `json:"temperature,omitempty"`
+	TopP                *float64                   `json:"top_p,omitempty"`
+	FrequencyPenalty    *float64                   `json:"frequency_penalty,omitempty"`
+	PresencePenalty     *float64                   `json:"presence_penalty,omitempty"`
+	Seed                *int                       `json:"seed,omitempty"`
+	Stop                Strings                    `json:"stop,omitempty"`
+	User                string                     `json:"user,omitempty"` // Accepted and ignored.
+}
+
+// Tool is a function the model may call. Parameters is a JSON schema.
+type Tool struct {
+	Type     string `json:"type"` // Always "function".
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+// ResponseFormat constrains the reply to JSON, optionally following a schema.
+type ResponseFormat struct {
+	Type       string `json:"type"` // "text", "json_object" or "json_schema".
+	JSONSchema *struct {
+		Name   string          `json:"name"`
+		Schema json.RawMessage `json:"schema"`
+		Strict bool            `json:"strict,omitempty"`
+	} `json:"json_schema,omitempty"`
+}
+
+// StreamOptions configures streamed replies.
+type StreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Send a final chunk with token usage.
+}
+
+// ChatCompletion is the reply to a non-streamed chat completion request.
+type ChatCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"` // "chat.completion".
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   *Usage       `json:"usage,omitempty"`
+}
+
+// ChatChoice is one reply of a chat completion.
+type ChatChoice struct {
+	Index        int                      `json:"index"`
+	Message      transcript.OpenAIMessage `json:"message"`
+	FinishReason string                   `json:"finish_reason"` // "stop", "length" or "tool_calls".
+}
+
+// ChatCompletionChunk is one server-sent event of a streamed chat completion.
+type ChatCompletionChunk struct {
+	ID      string        `json:"id"`
+	Object  string        `json:"object"` // "chat.completion.chunk".
+	Created int64         `json:"created"`
+	Model   string        `json:"model"`
+	Choices []ChunkChoice `json:"choices"`
+	Usage   *Usage        `json:"usage,omitempty"` // Only in the final chunk, if requested.
+}
+
+// ChunkChoice is the change to one reply carried by a chunk.
+type ChunkChoice struct {
+	Index        int        `json:"index"`
+	Delta        ChunkDelta `json:"delta"`
+	FinishReason *string    `json:"finish_reason"` // Null until the last chunk.
+}
+
+// ChunkDelta is new reply content.
+type ChunkDelta struct {
+	Role      string          `json:"role,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	ToolCalls []ChunkToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChunkToolCall is a tool call in a chunk. Ollama sends each call whole, so every call arrives in one piece.
+type ChunkToolCall struct {
+	Index int `json:"index"`
+	transcript.OpenAIToolCall
+}
+
+// Usage counts the tokens of a request.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// =========================
+// == Completions API ==
+// =========================
+
+// CompletionRequest is the body of POST /v1/completions.
+type CompletionRequest struct {
+	Model            string         `json:"model"`
+	Prompt           Strings        `json:"prompt"` // Only a single prompt is supported.
+	Suffix           string         `json:"suffix,omitempty"`
+	Stream           bool           `json:"stream,omitempty"`
+	StreamOptions    *StreamOptions `json:"stream_options,omitempty"`
+	N                *int           `json:"n,omitempty"` // Only 1 is supported.
+	MaxTokens        *int           `json:"max_tokens,omitempty"`
+	Temperature      *float64       `json:"temperature,omitempty"`
+	TopP             *float64       `json:"top_p,omitempty"`
+	FrequencyPenalty *float64       `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64       `json:"presence_penalty,omitempty"`
+	Seed             *int           `json:"seed,omitempty"`
+	Stop             Strings        `json:"stop,omitempty"`
+	User             string         `json:"user,omitempty"` // Accepted and ignored.
+}
+
+// Completion is the reply to a completion request, or one chunk of a streamed reply.
+type Completion struct {
+	ID      string             `json:"id"`
+	Object  string             `json:"object"` // "text_completion".
+	Created int64              `json:"created"`
+	Model   string             `json:"model"`
+	Choices []CompletionChoice `json:"choices"`
+	Usage   *Usage             `json:"usage,omitempty"`
+}
+
+// CompletionChoice is one generated text.
+type CompletionChoice struct {
+	Index        int     `json:"index"`
+	Text         string  `json:"text"`
+	FinishReason *string `json:"finish_reason"` // Null in all but the last chunk of a stream.
+}
+
+// =========================
+// == Embeddings API ==
+// =========================
+
+// EmbeddingRequest is the body of POST /v1/embeddings.
+type EmbeddingRequest struct {
+	Model          string  `json:"model"`
+	Input          Strings `json:"input"`
+	EncodingFormat string  `json:"encoding_format,omitempty"` // "float" (default) or "base64".
+	Dimensions     *int    `json:"dimensions,omitempty"`      // Not supported.
+	User           string  `json:"user,omitempty"`            // Accepted and ignored.
+}
+
+// EmbeddingList is the reply to an embedding request.
+type EmbeddingList struct {
+	Object string      `json:"object"` // "list".
+	Data   []Embedding `json:"data"`
+	Model  string      `json:"model"`
+	Usage  Usage       `json:"usage"` // Ollama does not report embedding token counts, so these are zero.
+}
+
+// Embedding is the vector of one input.
+type Embedding struct {
+	Object    string      `json:"object"`    // "embedding".
+	Embedding interface{} `json:"embedding"` // []float32, or a base64 string of little-endian float32s.
+	Index     int         `json:"index"`
+}
+
+// =========================
+// == Models API ==
+// =========================
+
+// ModelList is the reply to GET /v1/models.
+type ModelList struct {
+	Object string  `json:"object"` // "list".
+	Data   []Model `json:"data"`
+}
+
+// Model describes an available model.
+type Model struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"` // "model".
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// =========================
+// == Errors ==
+// =========================
+
+// ErrorResponse is the body of every failed request.
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure in OpenAI's terms.
+type ErrorDetail struct {
+	Message string  `json:"message"`
+	Type    string  `json:"type"` // e.g., "invalid_request_error".
+	Param   *string `json:"param"`
+	Code    *string `json:"code"` // e.g., "model_not_found".
+}
+
+// Strings is a string or an array of strings, as OpenAI accepts for prompts, inputs and stop sequences.
+type Strings []string
+
+// UnmarshalJSON accepts a string, an array of strings or null.
+func (s *Strings) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*s = nil
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		var list []string
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		*s = list
+		return nil
+	}
+	var one string
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*s = Strings{one}
+	return nil
+}
@@ -5,10 +5,10 @@ go 1.24
 require (
 	github.com/stretchr/testify v1.10.0
 	gopkg.in/dnaeon/go-vcr.v2 v2.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,245 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of JSON Schema understood by Ollama for structured outputs and tool parameters.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`                 // JSON type (e.g., "object", "string").
+	Description          string             `json:"description,omitempty"`          // Human-readable description.
+	Properties           map[string]*Schema `json:"properties,omitempty"`           // Object properties.
+	Required             []string           `json:"required,omitempty"`             // Required object properties.
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"` // Schema for map values.
+	Items                *Schema            `json:"items,omitempty"`                // Schema for array elements.
+	Enum                 []interface{}      `json:"enum,omitempty"`                 // Allowed values.
+	Format               string             `json:"format,omitempty"`               // Semantic format (e.g., "date-time").
+	Pattern              string             `json:"pattern,omitempty"`              // Regular expression for strings.
+	Minimum              *float64           `json:"minimum,omitempty"`              // Inclusive lower bound for numbers.
+	Maximum              *float64           `json:"maximum,omitempty"`              // Inclusive upper bound for numbers.
+	MinLength            *int               `json:"minLength,omitempty"`            // Minimum string length.
+	MaxLength            *int               `json:"maxLength,omitempty"`            // Maximum string length.
+	MinItems             *int               `json:"minItems,omitempty"`             // Minimum array length.
+	MaxItems             *int               `json:"maxItems,omitempty"`             // Maximum array length.
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// For derives a schema from the Go type T.
+//
+// Property names follow the `json` tag. A field is required unless it is a pointer, is tagged
+// `omitempty`, or carries `jsonschema:"optional"`. Descriptions come from the `description` tag and
+// constraints from the `jsonschema` tag, e.g. `jsonschema:"enum=celsius|fahrenheit"` or
+// `jsonschema:"minimum=0,maximum=100"`.
+func For[T any]() (*Schema, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect derives a schema from t. See For for the supported struct tags.
+func Reflect(t reflect.Type) (*Schema, error) {
+	return reflectType(t, map[reflect.Type]bool{})
+}
+
+func reflectType(t reflect.Type, seen map[reflect.Type]bool) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case t == rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"}, nil // Encoded as base64 by encoding/json
+		}
+		items, err := reflectType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("jsonschema: unsupported map key type %s", t.Key())
+		}
+		values, err := reflectType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if seen[t] {
+			return &Schema{Type: "object"}, nil // Recursive type: stop expanding
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		if err := addFields(schema, t, seen); err != nil {
+			return nil, err
+		}
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("jsonschema: unsupported type %s", t)
+}
+
+// addFields adds the exported fields of struct type t, flattening embedded structs like encoding/json.
+func addFields(schema *Schema, t reflect.Type, seen map[reflect.Type]bool) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && !strings.Contains(string(field.Tag), `json:"`) {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := addFields(schema, embedded, seen); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		prop, err := reflectType(field.Type, seen)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		prop.Description = field.Tag.Get("description")
+
+		required := !omitempty && field.Type.Kind() != reflect.Pointer
+		if err := applyTag(prop, field.Tag.Get("jsonschema"), &required); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+
+		schema.Properties[name] = prop
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// jsonName returns the JSON property name of field and whether it is tagged omitempty or skipped.
+func jsonName(field reflect.StructField) (name string, omitempty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitempty = true
+		}
+	}
+	return name, omitempty, false
+}
+
+// applyTag applies the constraints listed in a `jsonschema` struct tag.
+func applyTag(schema *Schema, tag string, required *bool) error {
+	if tag == "" {
+		return nil
+	}
+	for _, item := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(item), "=")
+		var err error
+		switch key {
+		case "required":
+			*required = true
+		case "optional":
+			*required = false
+		case "enum":
+			for _, v := range strings.Split(value, "|") {
+				schema.Enum = append(schema.Enum, enumValue(schema.Type, v))
+			}
+		case "format":
+			schema.Format = value
+		case "pattern":
+			schema.Pattern = value
+		case "minimum":
+			schema.Minimum, err = parseFloat(value)
+		case "maximum":
+			schema.Maximum, err = parseFloat(value)
+		case "minLength":
+			schema.MinLength, err = parseInt(value)
+		case "maxLength":
+			schema.MaxLength, err = parseInt(value)
+		case "minItems":
+			schema.MinItems, err = parseInt(value)
+		case "maxItems":
+			schema.MaxItems, err = parseInt(value)
+		default:
+			return fmt.Errorf("jsonschema: unknown tag option %q", key)
+		}
+		if err != nil {
+			return fmt.Errorf("jsonschema: invalid %s %q: %w", key, value, err)
+		}
+	}
+	return nil
+}
+
+// enumValue converts an enum tag value to the JSON type of the schema.
+func enumValue(typ, value string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return value
+}
+
+func parseFloat(value string) (*float64, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func parseInt(value string) (*int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
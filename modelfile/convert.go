@@ -0,0 +1,142 @@
+package modelfile
+
+import (
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BlobResolver uploads a local file and returns its blob digest; (*client.OllamaClient).UploadBlob fits once
+// bound to a context.
+type BlobResolver func(path string) (string, error)
+
+// IsLocalPath reports whether a FROM or ADAPTER value names a local file rather than a model.
+func IsLocalPath(value string) bool {
+	if value == "." || value == ".." {
+		return true
+	}
+	for _, prefix := range []string{"/", "./", "../", "~/", ".\\", "..\\"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	if filepath.VolumeName(value) != "" {
+		return true
+	}
+	ext := strings.ToLower(filepath.Ext(value))
+	return ext == ".gguf" || ext == ".safetensors" || ext == ".bin"
+}
+
+// CreateRequest converts the Modelfile to a create request for the model name. Local files named by FROM or
+// ADAPTER are passed to resolve, which must upload them and return their digests; resolve may be nil if the
+// Modelfile only refers to existing models.
+func (m *Modelfile) CreateRequest(name string, resolve BlobResolver) (structures.ModelManagementRequest, error) {
+	req := structures.ModelManagementRequest{Name: name}
+	if err := m.Validate(); err != nil {
+		return req, err
+	}
+
+	blob := func(path string) (string, string, error) {
+		if resolve == nil {
+			return "", "", fmt.Errorf("%w: %s is a local file but no blob resolver was given", utils.ErrInvalidModelfile, path)
+		}
+		digest, err := resolve(path)
+		return filepath.Base(path), digest, err
+	}
+
+	if from := m.From(); IsLocalPath(from) {
+		file, digest, err := blob(from)
+		if err != nil {
+			return req, err
+		}
+		req.Files = map[string]string{file: digest}
+	} else {
+		req.From = from
+	}
+
+	for _, adapter := range m.Adapters() {
+		file, digest, err := blob(adapter)
+		if err != nil {
+			return req, err
+		}
+		if req.Adapters == nil {
+			req.Adapters = map[string]string{}
+		}
+		req.Adapters[file] = digest
+	}
+
+	params, err := m.parameterMap()
+	if err != nil {
+		return req, err
+	}
+	if len(params) > 0 {
+		req.Parameters = params
+	}
+	req.Template = m.Template()
+	req.System = m.System()
+	req.License = m.Licenses()
+	req.Messages = m.Messages()
+	return req, nil
+}
+
+// FromCreateRequest converts a create request back to a Modelfile. Uploaded files and adapters are written
+// under their file names, since the local paths are not part of the request. Parameters are sorted by key.
+// The request's Quantize setting has no Modelfile equivalent and is dropped.
+func FromCreateRequest(req structures.ModelManagementRequest) (*Modelfile, error) {
+	b := &Builder{}
+	switch {
+	case req.From != "":
+		b.add(From, "", req.From)
+	case len(req.Files) == 1:
+		b.add(From, "", "./"+sortedKeys(req.Files)[0])
+	case len(req.Files) > 1:
+		b.add(From, "", ".") // Multi-file models (e.g., safetensors) are imported from a directory
+	}
+	for _, file := range sortedKeys(req.Adapters) {
+		b.add(Adapter, "", "./"+file)
+	}
+
+	names := make([]string, 0, len(req.Parameters))
+	for name := range req.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := reflect.ValueOf(req.Parameters[name])
+		if value.Kind() == reflect.Slice {
+			for i := 0; i < value.Len(); i++ {
+				b.Parameter(name, value.Index(i).Interface())
+			}
+			continue
+		}
+		b.Parameter(name, req.Parameters[name])
+	}
+
+	if req.Template != "" {
+		b.Template(req.Template)
+	}
+	if req.System != "" {
+		b.System(req.System)
+	}
+	for _, license := range req.License {
+		b.License(license)
+	}
+	for _, msg := range req.Messages {
+		b.Message(msg.Role, msg.Content)
+	}
+	return b.Build()
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package modelfile
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// String prints the Modelfile in a stable format: instructions grouped in the order FROM, ADAPTER, PARAMETER,
+// TEMPLATE, SYSTEM, LICENSE, MESSAGE (keeping their relative order within each group), one per line, with
+// values quoted only when needed. Printing a parsed Modelfile and parsing it again yields the same instructions.
+func (m *Modelfile) String() string {
+	var b strings.Builder
+	for _, cmd := range commandOrder {
+		for _, inst := range m.Instructions {
+			if inst.Command != cmd {
+				continue
+			}
+			b.WriteString(string(inst.Command))
+			if inst.Name != "" {
+				b.WriteString(" " + inst.Name)
+			}
+			b.WriteString(" " + quote(inst))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// WriteTo writes the formatted Modelfile to w.
+func (m *Modelfile) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, m.String())
+	return int64(n), err
+}
+
+// quote formats an instruction's value so that it parses back unchanged. Multi-line values (and templates) use
+// """ blocks; values with surrounding spaces or quotes, and stop sequences, use an escaped "..." string.
+// A multi-line value that itself contains """ or ends with a quote cannot be represented exactly.
+func quote(inst Instruction) string {
+	value := inst.Value
+	tripleSafe := !strings.Contains(value, `"""`) && !strings.HasSuffix(value, `"`)
+	multiline := strings.Contains(value, "\n")
+	switch {
+	case multiline || inst.Command == Template && tripleSafe:
+		return `"""` + value + `"""`
+	case value == "" || value != strings.TrimSpace(value) || strings.HasPrefix(value, `"`) ||
+		inst.Command == Template || inst.Command == Parameter && parameterKinds[inst.Name] == reflect.Slice:
+		return `"` + escaper.Replace(value) + `"`
+	}
+	return value
+}
+
+// escaper escapes a value for a "..." string.
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// formatValue renders a builder parameter value as Modelfile text.
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,168 @@
+// Package modelfile parses, validates, prints and builds Ollama Modelfiles, and converts them to and from
+// create requests.
+package modelfile
+
+import (
+	"github.com/SamyRai/ollama-go/structures"
+)
+
+// Command identifies a Modelfile instruction.
+type Command string
+
+// Modelfile instructions.
+const (
+	From      Command = "FROM"
+	Parameter Command = "PARAMETER"
+	Template  Command = "TEMPLATE"
+	System    Command = "SYSTEM"
+	Adapter   Command = "ADAPTER"
+	License   Command = "LICENSE"
+	Message   Command = "MESSAGE"
+)
+
+// commandOrder is the order in which instructions are printed.
+var commandOrder = []Command{From, Adapter, Parameter, Template, System, License, Message}
+
+// Instruction is a single Modelfile instruction.
+type Instruction struct {
+	Command Command // Instruction keyword.
+	Name    string  // PARAMETER key or MESSAGE role; empty for other commands.
+	Value   string  // Argument, with any quoting removed.
+	Line    int     // Line the instruction starts on (1-based); 0 if it was not parsed from text.
+}
+
+// Modelfile is a parsed Modelfile: its instructions in source order.
+type Modelfile struct {
+	Instructions []Instruction
+}
+
+// From returns the base model or model file named by FROM.
+func (m *Modelfile) From() string {
+	return m.last(From)
+}
+
+// Template returns the prompt template.
+func (m *Modelfile) Template() string {
+	return m.last(Template)
+}
+
+// System returns the system prompt.
+func (m *Modelfile) System() string {
+	return m.last(System)
+}
+
+// Adapters returns the paths of the LoRA adapters.
+func (m *Modelfile) Adapters() []string {
+	return m.all(Adapter)
+}
+
+// Licenses returns the license texts.
+func (m *Modelfile) Licenses() []string {
+	return m.all(License)
+}
+
+// Parameters returns the PARAMETER instructions in source order; keys such as stop may repeat.
+func (m *Modelfile) Parameters() []Instruction {
+	var params []Instruction
+	for _, inst := range m.Instructions {
+		if inst.Command == Parameter {
+			params = append(params, inst)
+		}
+	}
+	return params
+}
+
+// Messages returns the example conversation.
+func (m *Modelfile) Messages() []structures.Message {
+	var messages []structures.Message
+	for _, inst := range m.Instructions {
+		if inst.Command == Message {
+			messages = append(messages, structures.Message{Role: inst.Name, Content: inst.Value})
+		}
+	}
+	return messages
+}
+
+// last returns the value of the last cmd instruction, which takes precedence like on the server.
+func (m *Modelfile) last(cmd Command) string {
+	for i := len(m.Instructions) - 1; i >= 0; i-- {
+		if m.Instructions[i].Command == cmd {
+			return m.Instructions[i].Value
+		}
+	}
+	return ""
+}
+
+// all returns the values of every cmd instruction.
+func (m *Modelfile) all(cmd Command) []string {
+	var values []string
+	for _, inst := range m.Instructions {
+		if inst.Command == cmd {
+			values = append(values, inst.Value)
+		}
+	}
+	return values
+}
+
+// Builder assembles a Modelfile fluently.
+type Builder struct {
+	m Modelfile
+}
+
+// NewBuilder starts a Modelfile based on from (a model name or a local model file).
+func NewBuilder(from string) *Builder {
+	b := &Builder{}
+	return b.add(From, "", from)
+}
+
+// Build validates and returns the assembled Modelfile.
+func (b *Builder) Build() (*Modelfile, error) {
+	m := &Modelfile{Instructions: append([]Instruction(nil), b.m.Instructions...)}
+	return m, m.Validate()
+}
+
+// Parameter adds a default parameter; value is formatted with fmt's %v verb.
+func (b *Builder) Parameter(name string, value interface{}) *Builder {
+	return b.add(Parameter, name, formatValue(value))
+}
+
+// Template sets the prompt template.
+func (b *Builder) Template(v string) *Builder {
+	return b.set(Template, v)
+}
+
+// System sets the system prompt.
+func (b *Builder) System(v string) *Builder {
+	return b.set(System, v)
+}
+
+// Adapter adds a LoRA adapter.
+func (b *Builder) Adapter(path string) *Builder {
+	return b.add(Adapter, "", path)
+}
+
+// License adds a license text.
+func (b *Builder) License(v string) *Builder {
+	return b.add(License, "", v)
+}
+
+// Message adds a message to the example conversation.
+func (b *Builder) Message(role, content string) *Builder {
+	return b.add(Message, role, content)
+}
+
+// set replaces the value of a single-valued instruction, adding it if missing.
+func (b *Builder) set(cmd Command, value string) *Builder {
+	for i := range b.m.Instructions {
+		if b.m.Instructions[i].Command == cmd {
+			b.m.Instructions[i].Value = value
+			return b
+		}
+	}
+	return b.add(cmd, "", value)
+}
+
+func (b *Builder) add(cmd Command, name, value string) *Builder {
+	b.m.Instructions = append(b.m.Instructions, Instruction{Command: cmd, Name: name, Value: value})
+	return b
+}
@@ -0,0 +1,185 @@
+package modelfile
+
+import (
+	"fmt"
+	"github.com/SamyRai/ollama-go/utils"
+	"io"
+	"strings"
+)
+
+// ParseError reports a syntax error in a Modelfile.
+type ParseError struct {
+	Line int    // Line the error was found on (1-based).
+	Msg  string // Description of the problem.
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: line %d: %s", utils.ErrInvalidModelfile, e.Line, e.Msg)
+}
+
+// Unwrap allows errors.Is(err, utils.ErrInvalidModelfile).
+func (e *ParseError) Unwrap() error {
+	return utils.ErrInvalidModelfile
+}
+
+// Parse reads a Modelfile. Commands are case-insensitive and comments (lines starting with #) are dropped.
+// Arguments may be quoted with "..." (supporting \" and \\ escapes) or span several lines with """...""".
+// Parse only checks syntax; call Validate to check the instructions themselves.
+func Parse(r io.Reader) (*Modelfile, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseString(string(data))
+}
+
+// ParseString is like Parse but reads from a string.
+func ParseString(src string) (*Modelfile, error) {
+	p := &parser{src: strings.ReplaceAll(src, "\r\n", "\n"), line: 1}
+	m := &Modelfile{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return m, nil
+		}
+		inst, err := p.instruction()
+		if err != nil {
+			return nil, err
+		}
+		m.Instructions = append(m.Instructions, inst)
+	}
+}
+
+// parser is a cursor over Modelfile source.
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &ParseError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// skipBlank skips whitespace, blank lines and comment lines.
+func (p *parser) skipBlank() {
+	for !p.eof() {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t':
+			p.pos++
+		case c == '#':
+			for !p.eof() && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// skipSpaces skips spaces and tabs within a line.
+func (p *parser) skipSpaces() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// word reads up to the next whitespace.
+func (p *parser) word() string {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(" \t\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// instruction parses one instruction starting at the current position.
+func (p *parser) instruction() (Instruction, error) {
+	inst := Instruction{Line: p.line}
+	keyword := p.word()
+	inst.Command = Command(strings.ToUpper(keyword))
+
+	switch inst.Command {
+	case From, Template, System, Adapter, License:
+	case Parameter, Message:
+		p.skipSpaces()
+		inst.Name = p.word()
+		if inst.Name == "" {
+			return inst, p.errorf("%s requires a name and a value", inst.Command)
+		}
+		if inst.Command == Parameter {
+			inst.Name = strings.ToLower(inst.Name)
+		}
+	default:
+		return inst, p.errorf("unknown command %q", keyword)
+	}
+
+	p.skipSpaces()
+	value, err := p.value()
+	if err != nil {
+		return inst, err
+	}
+	inst.Value = value
+	return inst, nil
+}
+
+// value parses an argument, which is either quoted or runs to the end of the line.
+func (p *parser) value() (string, error) {
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], `"""`):
+		p.pos += 3
+		end := strings.Index(p.src[p.pos:], `"""`)
+		if end < 0 {
+			return "", p.errorf(`unterminated """ string`)
+		}
+		value := p.src[p.pos : p.pos+end]
+		p.line += strings.Count(value, "\n")
+		p.pos += end + 3
+		return value, p.endOfLine()
+	case strings.HasPrefix(p.src[p.pos:], `"`):
+		p.pos++
+		var b strings.Builder
+		for {
+			if p.eof() || p.src[p.pos] == '\n' {
+				return "", p.errorf(`unterminated " string`)
+			}
+			c := p.src[p.pos]
+			p.pos++
+			switch {
+			case c == '"':
+				return b.String(), p.endOfLine()
+			case c == '\\' && !p.eof() && (p.src[p.pos] == '"' || p.src[p.pos] == '\\'):
+				b.WriteByte(p.src[p.pos])
+				p.pos++
+			default:
+				b.WriteByte(c)
+			}
+		}
+	default:
+		start := p.pos
+		for !p.eof() && p.src[p.pos] != '\n' {
+			p.pos++
+		}
+		value := strings.TrimSpace(p.src[start:p.pos])
+		if value == "" {
+			return "", p.errorf("missing value")
+		}
+		return value, nil
+	}
+}
+
+// endOfLine checks that nothing but whitespace follows a quoted value.
+func (p *parser) endOfLine() error {
+	p.skipSpaces()
+	if !p.eof() && p.src[p.pos] != '\n' {
+		return p.errorf("unexpected text after quoted value")
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package modelfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// messageRoles are the roles a MESSAGE instruction may use.
+var messageRoles = map[string]bool{"system": true, "user": true, "assistant": true, "tool": true}
+
+// parameterKinds maps each PARAMETER key to the kind of value it takes, derived from structures.Options.
+var parameterKinds = func() map[string]reflect.Kind {
+	kinds := map[string]reflect.Kind{}
+	t := reflect.TypeOf(structures.Options{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		kind := field.Type.Elem().Kind() // Pointer or slice element
+		if field.Type.Kind() == reflect.Slice {
+			kind = reflect.Slice
+		}
+		kinds[name] = kind
+	}
+	return kinds
+}()
+
+// ParameterNames returns the PARAMETER keys accepted by Validate, sorted.
+func ParameterNames() []string {
+	names := make([]string, 0, len(parameterKinds))
+	for name := range parameterKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks the instructions: exactly one FROM, no repeated TEMPLATE or SYSTEM, known PARAMETER keys
+// with well-typed, in-range values, and valid MESSAGE roles. All problems are reported in a single error
+// wrapping utils.ErrInvalidModelfile.
+func (m *Modelfile) Validate() error {
+	var problems []string
+	fail := func(inst Instruction, format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if inst.Line > 0 {
+			msg = fmt.Sprintf("line %d: %s", inst.Line, msg)
+		}
+		problems = append(problems, msg)
+	}
+
+	counts := map[Command]int{}
+	for _, inst := range m.Instructions {
+		counts[inst.Command]++
+		switch inst.Command {
+		case From, Template, System:
+			if counts[inst.Command] == 2 {
+				fail(inst, "%s appears more than once", inst.Command)
+			}
+			if inst.Command == From && strings.TrimSpace(inst.Value) == "" {
+				fail(inst, "FROM requires a model name or file")
+			}
+		case Parameter:
+			if _, err := parameterValue(inst.Name, inst.Value); err != nil {
+				fail(inst, "%v", err)
+			}
+		case Message:
+			if !messageRoles[inst.Name] {
+				fail(inst, "invalid MESSAGE role %q", inst.Name)
+			}
+		case Adapter, License:
+		default:
+			fail(inst, "unknown command %q", inst.Command)
+		}
+	}
+	if counts[From] == 0 {
+		problems = append([]string{"missing FROM"}, problems...)
+	}
+
+	if len(problems) == 0 {
+		if _, err := m.Options(); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", utils.ErrInvalidModelfile, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Options converts the PARAMETER instructions to Options and checks their ranges.
+func (m *Modelfile) Options() (structures.Options, error) {
+	var opts structures.Options
+	params, err := m.parameterMap()
+	if err != nil {
+		return opts, err
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return opts, err
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, err
+	}
+	return opts, opts.Validate()
+}
+
+// parameterMap collects the PARAMETER instructions into the typed map sent in a create request.
+// Repeated keys (e.g., stop) become lists; otherwise the last value wins.
+func (m *Modelfile) parameterMap() (map[string]interface{}, error) {
+	params := map[string]interface{}{}
+	for _, inst := range m.Parameters() {
+		value, err := parameterValue(inst.Name, inst.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", utils.ErrInvalidModelfile, err)
+		}
+		if parameterKinds[inst.Name] == reflect.Slice {
+			list, _ := params[inst.Name].([]string)
+			params[inst.Name] = append(list, value.(string))
+			continue
+		}
+		params[inst.Name] = value
+	}
+	return params, nil
+}
+
+// parameterValue parses raw as the type expected for the parameter name.
+func parameterValue(name, raw string) (interface{}, error) {
+	kind, ok := parameterKinds[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown PARAMETER %q", name)
+	}
+	switch kind {
+	case reflect.Int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("PARAMETER %s: %q is not an integer", name, raw)
+		}
+		return v, nil
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("PARAMETER %s: %q is not a number", name, raw)
+		}
+		return v, nil
+	case reflect.Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("PARAMETER %s: %q is not a boolean", name, raw)
+		}
+		return v, nil
+	}
+	return raw, nil
+}
@@ -0,0 +1,636 @@
+package ollamatest
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"math"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultKeepAlive is how long a model stays loaded when the request does not say.
+const defaultKeepAlive = 5 * time.Minute
+
+// wordPattern splits replies into streamed chunks, keeping the trailing whitespace with each word.
+var wordPattern = regexp.MustCompile(`\s*\S+\s*`)
+
+// exchange handles a single request.
+type exchange struct {
+	s          *Server
+	w          http.ResponseWriter
+	r          *http.Request
+	body       []byte
+	script     *Response // Scripted response, if any
+	chunkDelay time.Duration
+}
+
+// raw sends a scripted response as is.
+func (x *exchange) raw() {
+	status := x.script.Status
+	if status == 0 && x.script.Error != "" {
+		status = http.StatusInternalServerError
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if x.script.Lines != nil {
+		x.stream(status, x.script.Lines)
+		x.disconnect()
+		return
+	}
+	x.disconnect()
+	switch {
+	case x.script.Error != "":
+		x.fail(status, x.script.Error)
+	case x.script.Body != nil:
+		x.json(status, x.script.Body)
+	default:
+		x.header(status, "")
+	}
+}
+
+// decode unmarshals the request body into v, answering 400 if it is invalid.
+func (x *exchange) decode(v interface{}) bool {
+	if err := json.Unmarshal(x.body, v); err != nil {
+		x.fail(http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// streaming reports whether the request asked for a streamed response, which is the default.
+func (x *exchange) streaming() bool {
+	var req struct {
+		Stream *bool `json:"stream"`
+	}
+	_ = json.Unmarshal(x.body, &req)
+	return req.Stream == nil || *req.Stream
+}
+
+func (x *exchange) header(status int, contentType string) {
+	if x.script != nil {
+		for key, values := range x.script.Header {
+			x.w.Header()[key] = values
+		}
+	}
+	if contentType != "" {
+		x.w.Header().Set("Content-Type", contentType)
+	}
+	x.w.WriteHeader(status)
+}
+
+func (x *exchange) json(status int, v interface{}) {
+	x.header(status, "application/json; charset=utf-8")
+	_ = json.NewEncoder(x.w).Encode(v)
+}
+
+func (x *exchange) fail(status int, message string) {
+	x.json(status, map[string]string{"error": message})
+}
+
+func (x *exchange) notFound(name string) {
+	x.fail(http.StatusNotFound, fmt.Sprintf("model %q not found, try pulling it first", name))
+}
+
+// stream sends lines as NDJSON, flushing each one. It returns false if the client went away.
+func (x *exchange) stream(status int, lines []interface{}) bool {
+	x.header(status, "application/x-ndjson")
+	flusher, _ := x.w.(http.Flusher)
+	enc := json.NewEncoder(x.w)
+	for i, line := range lines {
+		if i > 0 && !sleep(x.r, x.chunkDelay) {
+			return false
+		}
+		if err := enc.Encode(line); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return true
+}
+
+// disconnect drops the connection if the script asks for it.
+func (x *exchange) disconnect() {
+	if x.script != nil && x.script.Disconnect {
+		panic(http.ErrAbortHandler) // Closes the connection without finishing the response
+	}
+}
+
+// load marks a model as loaded for keepAlive, answering 404 if it is not installed.
+func (x *exchange) load(name string, keepAlive *structures.KeepAlive) (Model, bool) {
+	m, ok := x.s.model(name)
+	if !ok {
+		x.notFound(name)
+		return m, false
+	}
+	d := defaultKeepAlive
+	if keepAlive != nil {
+		d = keepAlive.Duration()
+	}
+
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+	switch {
+	case d == 0:
+		delete(x.s.running, m.Name)
+	case d < 0:
+		x.s.running[m.Name] = time.Now().AddDate(100, 0, 0) // Kept loaded indefinitely
+	default:
+		x.s.running[m.Name] = time.Now().Add(d)
+	}
+	return m, true
+}
+
+// reply returns the scripted reply, or an echo of input.
+func (x *exchange) reply(input string) Response {
+	var reply Response
+	if x.script != nil {
+		reply = *x.script
+	}
+	if reply.Content == "" && reply.ToolCalls == nil && reply.Chunks == nil {
+		reply.Content = input
+	}
+	if reply.Chunks == nil {
+		reply.Chunks = wordPattern.FindAllString(reply.Content, -1)
+	} else if reply.Content == "" {
+		reply.Content = strings.Join(reply.Chunks, "")
+	}
+	if reply.DoneReason == "" {
+		reply.DoneReason = "stop"
+	}
+	if reply.EvalTokens == 0 {
+		reply.EvalTokens = len(reply.Chunks)
+	}
+	return reply
+}
+
+// loadReason is the done reason of a request that only loads or unloads a model.
+func loadReason(keepAlive *structures.KeepAlive) string {
+	if keepAlive != nil && keepAlive.Duration() == 0 {
+		return "unload"
+	}
+	return "load"
+}
+
+func (x *exchange) generate() {
+	var req structures.CompletionRequest
+	if !x.decode(&req) {
+		return
+	}
+	if !x.streaming() {
+		x.disconnect()
+	}
+	if _, ok := x.load(req.Model, req.KeepAlive); !ok {
+		return
+	}
+	now := time.Now().UTC()
+	if req.Prompt == "" && len(req.Images) == 0 {
+		x.json(http.StatusOK, structures.CompletionResponse{Model: req.Model, CreatedAt: now, Done: true, DoneReason: loadReason(req.KeepAlive)})
+		return
+	}
+
+	reply := x.reply(req.Prompt)
+	final := structures.CompletionResponse{
+		Model: req.Model, CreatedAt: now, Done: true, DoneReason: reply.DoneReason,
+		PromptEvalCount: promptTokens(reply, req.Prompt), EvalCount: reply.EvalTokens,
+		TotalDuration: int64(time.Millisecond), PromptEvalDuration: int64(100 * time.Microsecond), EvalDuration: int64(500 * time.Microsecond),
+	}
+	if !x.streaming() {
+		final.Response = reply.Content
+		x.json(http.StatusOK, final)
+		return
+	}
+
+	var lines []interface{}
+	for _, chunk := range reply.Chunks {
+		lines = append(lines, structures.CompletionResponse{Model: req.Model, CreatedAt: now, Response: chunk})
+	}
+	if x.script == nil || !x.script.Disconnect {
+		lines = append(lines, final)
+	}
+	x.stream(http.StatusOK, lines)
+	x.disconnect()
+}
+
+func (x *exchange) chat() {
+	var req structures.ChatRequest
+	if !x.decode(&req) {
+		return
+	}
+	if !x.streaming() {
+		x.disconnect()
+	}
+	if _, ok := x.load(req.Model, req.KeepAlive); !ok {
+		return
+	}
+	now := time.Now().UTC()
+	if len(req.Messages) == 0 {
+		x.json(http.StatusOK, structures.ChatResponse{
+			Model: req.Model, CreatedAt: now, Message: structures.Message{Role: "assistant"}, Done: true, DoneReason: loadReason(req.KeepAlive),
+		})
+		return
+	}
+
+	var prompt []string
+	for _, msg := range req.Messages {
+		prompt = append(prompt, msg.Content)
+	}
+	reply := x.reply(req.Messages[len(req.Messages)-1].Content)
+	final := structures.ChatResponse{
+		Model: req.Model, CreatedAt: now, Message: structures.Message{Role: "assistant"}, Done: true, DoneReason: reply.DoneReason,
+		PromptEvalCount: promptTokens(reply, strings.Join(prompt, " ")), EvalCount: reply.EvalTokens,
+		TotalDuration: int64(time.Millisecond), PromptEvalDuration: int64(100 * time.Microsecond), EvalDuration: int64(500 * time.Microsecond),
+	}
+	if !x.streaming() {
+		final.Message.Content = reply.Content
+		final.Message.ToolCalls = reply.ToolCalls
+		x.json(http.StatusOK, final)
+		return
+	}
+
+	var lines []interface{}
+	for _, chunk := range reply.Chunks {
+		lines = append(lines, structures.ChatResponse{Model: req.Model, CreatedAt: now, Message: structures.Message{Role: "assistant", Content: chunk}})
+	}
+	if len(reply.ToolCalls) > 0 {
+		lines = append(lines, structures.ChatResponse{Model: req.Model, CreatedAt: now, Message: structures.Message{Role: "assistant", ToolCalls: reply.ToolCalls}})
+	}
+	if x.script == nil || !x.script.Disconnect {
+		lines = append(lines, final)
+	}
+	x.stream(http.StatusOK, lines)
+	x.disconnect()
+}
+
+// promptTokens returns the scripted prompt size, or the prompt's word count.
+func promptTokens(reply Response, prompt string) int {
+	if reply.PromptTokens > 0 {
+		return reply.PromptTokens
+	}
+	return len(strings.Fields(prompt))
+}
+
+func (x *exchange) embed() {
+	var req struct {
+		Model     string                `json:"model"`
+		Input     interface{}           `json:"input"`
+		KeepAlive *structures.KeepAlive `json:"keep_alive"`
+	}
+	if !x.decode(&req) {
+		return
+	}
+	var inputs []string
+	switch input := req.Input.(type) {
+	case string:
+		inputs = []string{input}
+	case []interface{}:
+		for _, item := range input {
+			text, ok := item.(string)
+			if !ok {
+				x.fail(http.StatusBadRequest, "invalid input type")
+				return
+			}
+			inputs = append(inputs, text)
+		}
+	default:
+		x.fail(http.StatusBadRequest, "invalid input type")
+		return
+	}
+	m, ok := x.load(req.Model, req.KeepAlive)
+	if !ok {
+		return
+	}
+
+	resp := structures.EmbeddingResponse{Model: req.Model, Embeddings: make([][]float32, len(inputs))}
+	for i, input := range inputs {
+		resp.Embeddings[i] = Embedding(input, m.EmbeddingLength)
+	}
+	x.json(http.StatusOK, resp)
+}
+
+// Embedding returns the deterministic unit vector the server uses as the embedding of text.
+func Embedding(text string, dims int) []float32 {
+	vector := make([]float32, dims)
+	var norm float64
+	for i := range vector {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", i, text)))
+		v := float64(binary.BigEndian.Uint32(sum[:4]))/math.MaxUint32*2 - 1
+		vector[i] = float32(v)
+		norm += v * v
+	}
+	for i := range vector {
+		vector[i] = float32(float64(vector[i]) / math.Sqrt(norm))
+	}
+	return vector
+}
+
+func (x *exchange) tags() {
+	resp := structures.ModelListResponse{Models: []structures.ModelInfo{}}
+	for _, m := range x.s.Models() {
+		resp.Models = append(resp.Models, structures.ModelInfo{
+			Name: m.Name, ModifiedAt: m.ModifiedAt, Size: m.Size, Digest: m.Digest, Details: details(m),
+		})
+	}
+	x.json(http.StatusOK, resp)
+}
+
+func (x *exchange) show() {
+	name, ok := x.modelName()
+	if !ok {
+		return
+	}
+	m, ok := x.s.model(name)
+	if !ok {
+		x.notFound(name)
+		return
+	}
+	x.json(http.StatusOK, structures.ShowModelResponse{
+		Modelfile:  fmt.Sprintf("# Modelfile generated by ollamatest\nFROM %s\n", m.Name),
+		Parameters: m.Parameters,
+		Template:   m.Template,
+		System:     m.System,
+		Details:    details(m),
+		ModelInfo: structures.ModelMetadata{
+			"general.architecture":         m.Family,
+			m.Family + ".context_length":   m.ContextLength,
+			m.Family + ".embedding_length": m.EmbeddingLength,
+		},
+		Capabilities: m.Capabilities,
+		ModifiedAt:   m.ModifiedAt,
+	})
+}
+
+func (x *exchange) ps() {
+	x.s.mu.Lock()
+	x.s.expire()
+	resp := structures.ModelProcessResponse{Models: []structures.ModelProcess{}}
+	for name, expires := range x.s.running {
+		m, ok := x.s.models[name]
+		if !ok {
+			continue
+		}
+		resp.Models = append(resp.Models, structures.ModelProcess{
+			Name: m.Name, Model: m.Name, Size: m.Size, Digest: m.Digest, ExpiresAt: expires, VRAMSize: m.Size, Details: details(*m),
+		})
+	}
+	x.s.mu.Unlock()
+	sort.Slice(resp.Models, func(i, j int) bool { return resp.Models[i].Name < resp.Models[j].Name })
+	x.json(http.StatusOK, resp)
+}
+
+func (x *exchange) version() {
+	x.s.mu.Lock()
+	version := x.s.Version
+	x.s.mu.Unlock()
+	if version == "" {
+		version = DefaultVersion
+	}
+	x.json(http.StatusOK, structures.VersionResponse{Version: version})
+}
+
+// modelRequest is the body of show, pull, push and delete requests. Older clients send "name".
+type modelRequest struct {
+	Model string `json:"model"`
+	Name  string `json:"name"`
+}
+
+// modelName reads the model named by a modelRequest body.
+func (x *exchange) modelName() (string, bool) {
+	var req modelRequest
+	if !x.decode(&req) {
+		return "", false
+	}
+	if req.Model == "" {
+		req.Model = req.Name
+	}
+	if req.Model == "" {
+		x.fail(http.StatusBadRequest, "model is required")
+		return "", false
+	}
+	return req.Model, true
+}
+
+func (x *exchange) pull() {
+	name, ok := x.modelName()
+	if !ok {
+		return
+	}
+	m, installed := x.s.model(name)
+	if !installed {
+		sum := sha256.Sum256([]byte(name))
+		m = Model{Name: name, Digest: hex.EncodeToString(sum[:]), Size: 1 << 20}
+	}
+	layer, short := "sha256:"+m.Digest, shortDigest(m.Digest)
+	x.progress([]structures.ProgressResponse{
+		{Status: "pulling manifest"},
+		{Status: "pulling " + short, Digest: layer, Total: m.Size},
+		{Status: "pulling " + short, Digest: layer, Total: m.Size, Completed: m.Size / 2},
+		{Status: "pulling " + short, Digest: layer, Total: m.Size, Completed: m.Size},
+		{Status: "verifying sha256 digest"},
+		{Status: "writing manifest"},
+	}, func() {
+		if !installed {
+			x.s.AddModel(m)
+		}
+	})
+}
+
+func (x *exchange) push() {
+	name, ok := x.modelName()
+	if !ok {
+		return
+	}
+	m, installed := x.s.model(name)
+	if !installed {
+		x.notFound(name)
+		return
+	}
+	layer, short := "sha256:"+m.Digest, shortDigest(m.Digest)
+	x.progress([]structures.ProgressResponse{
+		{Status: "retrieving manifest"},
+		{Status: "pushing " + short, Digest: layer, Total: m.Size},
+		{Status: "pushing " + short, Digest: layer, Total: m.Size, Completed: m.Size},
+		{Status: "pushing manifest"},
+	}, func() {})
+}
+
+// shortDigest returns the first 12 characters of a digest, as the server shows layers.
+func shortDigest(digest string) string {
+	if len(digest) > 12 {
+		return digest[:12]
+	}
+	return digest
+}
+
+func (x *exchange) create() {
+	var req struct {
+		structures.ModelManagementRequest
+		Model string `json:"model"`
+	}
+	if !x.decode(&req) {
+		return
+	}
+	name := req.Model
+	if name == "" {
+		name = req.Name
+	}
+	if name == "" {
+		x.fail(http.StatusBadRequest, "model is required")
+		return
+	}
+	if req.From == "" && len(req.Files) == 0 {
+		x.fail(http.StatusBadRequest, "neither 'from' or 'files' was specified")
+		return
+	}
+
+	m := Model{Name: name}
+	var updates []structures.ProgressResponse
+	if req.From != "" {
+		base, ok := x.s.model(req.From)
+		if !ok {
+			x.notFound(req.From)
+			return
+		}
+		m = base
+		m.Name, m.ModifiedAt = name, time.Time{}
+		updates = append(updates, structures.ProgressResponse{Status: "using existing layer sha256:" + base.Digest})
+	}
+	for _, files := range []map[string]string{req.Files, req.Adapters} {
+		for _, file := range sortedKeys(files) {
+			digest := files[file]
+			if _, ok := x.s.Blob(digest); !ok {
+				x.fail(http.StatusBadRequest, fmt.Sprintf("blob %s for %s not found", digest, file))
+				return
+			}
+			updates = append(updates, structures.ProgressResponse{Status: "using existing layer " + digest})
+		}
+	}
+
+	sum := sha256.Sum256(x.body)
+	m.Digest = hex.EncodeToString(sum[:])
+	if req.Template != "" {
+		m.Template = req.Template
+	}
+	if req.System != "" {
+		m.System = req.System
+	}
+	if req.Quantize != "" {
+		m.Quantization = strings.ToUpper(req.Quantize)
+	}
+	if len(req.Parameters) > 0 {
+		var params []string
+		for _, key := range sortedKeys(req.Parameters) {
+			params = append(params, fmt.Sprintf("%s %v", key, req.Parameters[key]))
+		}
+		m.Parameters = strings.Join(params, "\n")
+	}
+	updates = append(updates, structures.ProgressResponse{Status: "writing manifest"})
+	x.progress(updates, func() { x.s.AddModel(m) })
+}
+
+func (x *exchange) copy() {
+	var req struct {
+		Source      string `json:"source"`
+		Destination string `json:"destination"`
+	}
+	if !x.decode(&req) {
+		return
+	}
+	if req.Source == "" || req.Destination == "" {
+		x.fail(http.StatusBadRequest, "source and destination are required")
+		return
+	}
+	m, ok := x.s.model(req.Source)
+	if !ok {
+		x.notFound(req.Source)
+		return
+	}
+	m.Name, m.ModifiedAt = req.Destination, time.Time{}
+	x.s.AddModel(m)
+	x.header(http.StatusOK, "")
+}
+
+func (x *exchange) delete() {
+	name, ok := x.modelName()
+	if !ok {
+		return
+	}
+	if !x.s.RemoveModel(name) {
+		x.fail(http.StatusNotFound, fmt.Sprintf("model %q not found", name))
+		return
+	}
+	x.header(http.StatusOK, "")
+}
+
+func (x *exchange) blob() {
+	digest := strings.TrimPrefix(x.r.URL.Path, "/api/blobs/")
+	if x.r.Method == http.MethodHead {
+		if _, ok := x.s.Blob(digest); !ok {
+			x.header(http.StatusNotFound, "")
+			return
+		}
+		x.header(http.StatusOK, "")
+		return
+	}
+	if got := x.s.AddBlob(x.body); got != digest {
+		x.s.mu.Lock()
+		delete(x.s.blobs, got)
+		x.s.mu.Unlock()
+		x.fail(http.StatusBadRequest, "digest mismatch")
+		return
+	}
+	x.header(http.StatusCreated, "")
+}
+
+// progress sends status updates followed by "success", running commit just before success is reported.
+// Without streaming, only the final status is sent.
+func (x *exchange) progress(updates []structures.ProgressResponse, commit func()) {
+	success := structures.ProgressResponse{Status: "success"}
+	if !x.streaming() {
+		x.disconnect()
+		commit()
+		x.json(http.StatusOK, success)
+		return
+	}
+	lines := make([]interface{}, len(updates))
+	for i, update := range updates {
+		lines[i] = update
+	}
+	if !x.stream(http.StatusOK, lines) {
+		return
+	}
+	x.disconnect()
+	commit()
+	if sleep(x.r, x.chunkDelay) {
+		_ = json.NewEncoder(x.w).Encode(success)
+	}
+}
+
+// details describes a model in list, ps and show responses.
+func details(m Model) structures.ModelDetails {
+	return structures.ModelDetails{
+		Format:        "gguf",
+		Family:        m.Family,
+		Families:      []string{m.Family},
+		ParameterSize: m.ParameterSize,
+		Quantization:  m.Quantization,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
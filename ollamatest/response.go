@@ -0,0 +1,65 @@
+package ollamatest
+
+import (
+	"github.com/SamyRai/ollama-go/structures"
+	"net/http"
+	"time"
+)
+
+// Response scripts the server's reply to one request.
+//
+// For /api/chat and /api/generate, Content, ToolCalls, Chunks, DoneReason and the token counts replace the
+// generated reply, which is still sent whole or as NDJSON chunks depending on the request's stream flag.
+// Setting Status, Error, Body or Lines instead sends exactly that, on any endpoint.
+type Response struct {
+	Content      string                // Reply text (by default the last message or prompt is echoed).
+	ToolCalls    []structures.ToolCall // Tool calls made by the reply (chat only).
+	Chunks       []string              // Pieces the reply is streamed in (word by word if empty).
+	DoneReason   string                // "stop" if empty.
+	PromptTokens int                   // Reported prompt size (the prompt's word count if zero).
+	EvalTokens   int                   // Reported reply size (the number of chunks if zero).
+
+	Status int           // HTTP status (200, or 500 if Error is set).
+	Error  string        // Sent as {"error": Error}.
+	Body   interface{}   // Sent as a JSON document.
+	Lines  []interface{} // Sent as NDJSON lines, e.g. progress updates.
+	Header http.Header   // Extra response headers (e.g., Retry-After).
+
+	Delay      time.Duration // Latency before the response, on top of Server.Latency.
+	ChunkDelay time.Duration // Latency between streamed lines (Server.ChunkDelay if zero).
+	Disconnect bool          // Drop the connection instead of finishing: after any streamed lines, otherwise at once.
+}
+
+// raw reports whether the response replaces the endpoint's behavior entirely.
+func (r *Response) raw() bool {
+	return r.Status != 0 || r.Error != "" || r.Body != nil || r.Lines != nil
+}
+
+// Reply returns a response with the given reply text.
+func Reply(content string) Response {
+	return Response{Content: content}
+}
+
+// Chunks returns a response streamed in the given pieces.
+func Chunks(chunks ...string) Response {
+	return Response{Chunks: chunks}
+}
+
+// ToolCall returns a chat response that calls the named tool.
+func ToolCall(name string, args map[string]interface{}) Response {
+	return Response{ToolCalls: []structures.ToolCall{{Function: structures.ToolCallFunction{Name: name, Arguments: args}}}}
+}
+
+// Fail returns a response failing with the HTTP status and error message.
+func Fail(status int, message string) Response {
+	return Response{Status: status, Error: message}
+}
+
+// Progress returns a response streaming the given status updates, for pull, push and create.
+func Progress(updates ...structures.ProgressResponse) Response {
+	lines := make([]interface{}, len(updates))
+	for i, update := range updates {
+		lines[i] = update
+	}
+	return Response{Lines: lines}
+}
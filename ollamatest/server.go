@@ -0,0 +1,386 @@
+// Package ollamatest provides an in-process fake Ollama server for tests. It implements the API the client
+// uses (generate, chat, embed, tags, show, ps, version, pull, push, create, copy, delete and blobs) with
+// in-memory state, lets tests script replies, latency and failures per endpoint, and records every request.
+//
+//	srv := ollamatest.NewTestServer(t)
+//	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+//	srv.Enqueue("/api/chat", ollamatest.Response{Content: "Hello!"})
+//	resp, err := srv.Client().ChatContext(ctx, req, nil)
+package ollamatest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// DefaultVersion is the version reported by /api/version unless Server.Version is set.
+const DefaultVersion = "0.0.0-ollamatest"
+
+// Server is a fake Ollama server listening on a local port. Its methods are safe for concurrent use.
+type Server struct {
+	URL        string        // Base URL of the server, for config.Config.BaseURL.
+	Version    string        // Reported by /api/version (DefaultVersion if empty).
+	Latency    time.Duration // Added before every response.
+	ChunkDelay time.Duration // Added between streamed chunks.
+
+	srv      *httptest.Server
+	mu       sync.Mutex
+	models   map[string]*Model
+	running  map[string]time.Time // Loaded models and when they expire
+	blobs    map[string][]byte
+	queued   map[string][]Response
+	handlers map[string]func(Request) Response
+	requests []Request
+}
+
+// Model is a model installed on the fake server. Zero fields get defaults when it is added.
+type Model struct {
+	Name            string   // Model name; ":latest" is added if it has no tag.
+	Digest          string   // Manifest digest (derived from the name if empty).
+	Size            int64    // Size in bytes (1 GiB if zero).
+	Family          string   // Model family, also used as the architecture ("llama" if empty).
+	ParameterSize   string   // e.g., "3.2B".
+	Quantization    string   // e.g., "Q4_K_M".
+	Capabilities    []string // Reported by /api/show ("completion" if empty).
+	ContextLength   int      // Reported in the model metadata (4096 if zero).
+	EmbeddingLength int      // Length of vectors returned by /api/embed (8 if zero).
+	Template        string
+	System          string
+	Parameters      string // Default parameters, one "key value" per line.
+	ModifiedAt      time.Time
+}
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+	Time   time.Time
+}
+
+// Decode unmarshals the JSON request body into v.
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// NewServer starts a fake server with no models. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		models:   map[string]*Model{},
+		running:  map[string]time.Time{},
+		blobs:    map[string][]byte{},
+		queued:   map[string][]Response{},
+		handlers: map[string]func(Request) Response{},
+	}
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// NewTestServer starts a fake server that is closed when the test finishes.
+func NewTestServer(tb testing.TB) *Server {
+	s := NewServer()
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client returns a client for the server, without retries or a default timeout.
+func (s *Server) Client() *client.OllamaClient {
+	return client.NewClient(&config.Config{BaseURL: s.URL})
+}
+
+// AddModel installs models on the server.
+func (s *Server) AddModel(models ...Model) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, m := range models {
+		s.addModel(m)
+	}
+}
+
+// RemoveModel uninstalls a model, reporting whether it was installed.
+func (s *Server) RemoveModel(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name = normalizeName(name)
+	_, ok := s.models[name]
+	delete(s.models, name)
+	delete(s.running, name)
+	return ok
+}
+
+// Models returns the installed models, sorted by name.
+func (s *Server) Models() []Model {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	models := make([]Model, 0, len(s.models))
+	for _, m := range s.models {
+		models = append(models, *m)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Name < models[j].Name })
+	return models
+}
+
+// Running returns the names of the models loaded in memory, sorted.
+func (s *Server) Running() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	names := make([]string, 0, len(s.running))
+	for name := range s.running {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AddBlob stores a blob and returns its digest.
+func (s *Server) AddBlob(data []byte) string {
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[digest] = append([]byte(nil), data...)
+	return digest
+}
+
+// Blob returns the blob with the given digest.
+func (s *Server) Blob(digest string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.blobs[digest]
+	return data, ok
+}
+
+// Enqueue scripts the next responses for an endpoint (e.g., "/api/chat", or "/api/blobs" for every blob
+// path). Each request consumes one response; when the queue is empty the endpoint behaves normally again.
+func (s *Server) Enqueue(endpoint string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queued[endpoint] = append(s.queued[endpoint], responses...)
+}
+
+// Handle scripts every response for an endpoint with fn, until Reset. Queued responses take precedence.
+func (s *Server) Handle(endpoint string, fn func(Request) Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = fn
+}
+
+// Requests returns the requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestsTo returns the requests received by an endpoint, oldest first.
+func (s *Server) RequestsTo(endpoint string) []Request {
+	var requests []Request
+	for _, req := range s.Requests() {
+		if route(req.Path) == endpoint {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// LastRequest returns the most recent request to an endpoint.
+func (s *Server) LastRequest(endpoint string) (Request, bool) {
+	requests := s.RequestsTo(endpoint)
+	if len(requests) == 0 {
+		return Request{}, false
+	}
+	return requests[len(requests)-1], true
+}
+
+// Reset forgets recorded requests and scripted responses. Models and blobs are kept.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.queued = map[string][]Response{}
+	s.handlers = map[string]func(Request) Response{}
+}
+
+// serveHTTP records the request, applies latency and any scripted response, then routes it.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := Request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body, Time: time.Now()}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	endpoint := route(r.URL.Path)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	var script *Response
+	if queue := s.queued[endpoint]; len(queue) > 0 {
+		script, s.queued[endpoint] = &queue[0], queue[1:]
+	} else if fn := s.handlers[endpoint]; fn != nil {
+		s.mu.Unlock()
+		resp := fn(req)
+		script = &resp
+		s.mu.Lock()
+	}
+	latency, chunkDelay := s.Latency, s.ChunkDelay
+	s.mu.Unlock()
+
+	x := &exchange{s: s, w: w, r: r, body: body, script: script, chunkDelay: chunkDelay}
+	if script != nil {
+		latency += script.Delay
+		if script.ChunkDelay > 0 {
+			x.chunkDelay = script.ChunkDelay
+		}
+	}
+	if !sleep(r, latency) {
+		return
+	}
+	if script != nil && script.raw() {
+		x.raw()
+		return
+	}
+	if !streamable(endpoint) {
+		x.disconnect()
+	}
+
+	switch {
+	case endpoint == "/api/generate" && r.Method == http.MethodPost:
+		x.generate()
+	case endpoint == "/api/chat" && r.Method == http.MethodPost:
+		x.chat()
+	case endpoint == "/api/embed" && r.Method == http.MethodPost:
+		x.embed()
+	case endpoint == "/api/tags" && r.Method == http.MethodGet:
+		x.tags()
+	case endpoint == "/api/show" && r.Method == http.MethodPost:
+		x.show()
+	case endpoint == "/api/ps" && r.Method == http.MethodGet:
+		x.ps()
+	case endpoint == "/api/version" && r.Method == http.MethodGet:
+		x.version()
+	case endpoint == "/api/pull" && r.Method == http.MethodPost:
+		x.pull()
+	case endpoint == "/api/push" && r.Method == http.MethodPost:
+		x.push()
+	case endpoint == "/api/create" && r.Method == http.MethodPost:
+		x.create()
+	case endpoint == "/api/copy" && r.Method == http.MethodPost:
+		x.copy()
+	case endpoint == "/api/delete" && r.Method == http.MethodDelete:
+		x.delete()
+	case endpoint == "/api/blobs" && (r.Method == http.MethodHead || r.Method == http.MethodPost):
+		x.blob()
+	case endpoint == "/" && r.Method == http.MethodGet:
+		io.WriteString(w, "Ollama is running")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// streamable reports whether an endpoint can stream, in which case a scripted disconnect happens mid-stream.
+func streamable(endpoint string) bool {
+	switch endpoint {
+	case "/api/generate", "/api/chat", "/api/pull", "/api/push", "/api/create":
+		return true
+	}
+	return false
+}
+
+// route maps a request path to the endpoint name used for scripting.
+func route(path string) string {
+	if strings.HasPrefix(path, "/api/blobs/") {
+		return "/api/blobs"
+	}
+	return path
+}
+
+// addModel fills in defaults and installs m. The caller holds s.mu.
+func (s *Server) addModel(m Model) *Model {
+	m.Name = normalizeName(m.Name)
+	if m.Digest == "" {
+		sum := sha256.Sum256([]byte(m.Name))
+		m.Digest = hex.EncodeToString(sum[:])
+	}
+	if m.Size == 0 {
+		m.Size = 1 << 30
+	}
+	if m.Family == "" {
+		m.Family = "llama"
+	}
+	if len(m.Capabilities) == 0 {
+		m.Capabilities = []string{structures.CapabilityCompletion}
+	}
+	if m.ContextLength == 0 {
+		m.ContextLength = 4096
+	}
+	if m.EmbeddingLength == 0 {
+		m.EmbeddingLength = 8
+	}
+	if m.ModifiedAt.IsZero() {
+		m.ModifiedAt = time.Now().UTC()
+	}
+	s.models[m.Name] = &m
+	return &m
+}
+
+// model returns a copy of an installed model.
+func (s *Server) model(name string) (Model, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.models[normalizeName(name)]
+	if !ok {
+		return Model{}, false
+	}
+	return *m, true
+}
+
+// expire unloads models whose keep-alive has passed. The caller holds s.mu.
+func (s *Server) expire() {
+	now := time.Now()
+	for name, expires := range s.running {
+		if now.After(expires) {
+			delete(s.running, name)
+		}
+	}
+}
+
+// normalizeName adds the default ":latest" tag.
+func normalizeName(name string) string {
+	if i := strings.LastIndex(name, "/"); strings.Contains(name[i+1:], ":") {
+		return name
+	}
+	return name + ":latest"
+}
+
+// sleep waits for d, returning false if the client went away first.
+func sleep(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
@@ -0,0 +1,228 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// FileFormat selects how a FileStore encodes sessions.
+type FileFormat string
+
+// Supported file formats.
+const (
+	FormatJSON  FileFormat = "json"  // One indented JSON document per session.
+	FormatJSONL FileFormat = "jsonl" // A header line followed by one line per message, convenient for grep and jq.
+)
+
+// FileStore keeps each session in its own file under Dir. Writes go to a temporary file that is renamed into
+// place, so readers never see a partial session. Version checks are serialized within the process; separate
+// processes sharing a directory should each hold their own sessions.
+type FileStore struct {
+	Dir    string
+	Format FileFormat // FormatJSON if empty.
+
+	mu sync.Mutex
+}
+
+// NewFileStore creates a store in dir, creating the directory if needed.
+func NewFileStore(dir string, format FileFormat) (*FileStore, error) {
+	if format == "" {
+		format = FormatJSON
+	}
+	if format != FormatJSON && format != FormatJSONL {
+		return nil, fmt.Errorf("unsupported session file format %q", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FileStore{Dir: dir, Format: format}, nil
+}
+
+// Save implements Store.
+func (f *FileStore) Save(ctx context.Context, rec *Record) error {
+	if err := validateID(rec.ID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var current int64
+	stored, err := f.read(rec.ID, true)
+	switch {
+	case err == nil:
+		current = stored.Version
+	case !errors.Is(err, utils.ErrSessionNotFound):
+		return err
+	}
+
+	next := *rec
+	if _, err := stamp(&next, current); err != nil {
+		return err
+	}
+	data, err := f.encode(&next)
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(f.path(rec.ID), data); err != nil {
+		return err
+	}
+	*rec = next
+	return nil
+}
+
+// Load implements Store.
+func (f *FileStore) Load(_ context.Context, id string) (*Record, error) {
+	if err := validateID(id); err != nil {
+		return nil, err
+	}
+	return f.read(id, false)
+}
+
+// List implements Store.
+func (f *FileStore) List(_ context.Context) ([]Info, error) {
+	entries, err := os.ReadDir(f.Dir)
+	if err != nil {
+		return nil, err
+	}
+	suffix := "." + string(f.format())
+	var infos []Info
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), suffix)
+		if !ok || entry.IsDir() || validateID(id) != nil {
+			continue
+		}
+		rec, err := f.read(id, false)
+		if errors.Is(err, utils.ErrSessionNotFound) {
+			continue // Deleted since listing
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, rec.info())
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Delete implements Store.
+func (f *FileStore) Delete(_ context.Context, id string) error {
+	if err := validateID(id); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	err := os.Remove(f.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", utils.ErrSessionNotFound, id)
+	}
+	return err
+}
+
+func (f *FileStore) format() FileFormat {
+	if f.Format == "" {
+		return FormatJSON
+	}
+	return f.Format
+}
+
+func (f *FileStore) path(id string) string {
+	return filepath.Join(f.Dir, id+"."+string(f.format()))
+}
+
+// read loads a session; with headerOnly, JSONL messages are skipped.
+func (f *FileStore) read(id string, headerOnly bool) (*Record, error) {
+	file, err := os.Open(f.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", utils.ErrSessionNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rec := &Record{}
+	if f.format() == FormatJSON {
+		if err := json.NewDecoder(file).Decode(rec); err != nil {
+			return nil, fmt.Errorf("decoding session %s: %w", id, err)
+		}
+		return rec, nil
+	}
+
+	reader := bufio.NewReader(file)
+	for line := 0; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			if line == 0 {
+				err = json.Unmarshal(data, rec)
+			} else {
+				var msg structures.Message
+				err = json.Unmarshal(data, &msg)
+				rec.Messages = append(rec.Messages, msg)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("decoding session %s line %d: %w", id, line+1, err)
+			}
+		}
+		if err == io.EOF || headerOnly {
+			return rec, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// encode serializes a record in the store's format.
+func (f *FileStore) encode(rec *Record) ([]byte, error) {
+	if f.format() == FormatJSON {
+		data, err := json.MarshalIndent(rec, "", "  ")
+		return append(data, '\n'), err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	header := *rec
+	header.Messages = nil
+	if err := enc.Encode(header); err != nil {
+		return nil, err
+	}
+	for _, msg := range rec.Messages {
+		if err := enc.Encode(msg); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// writeAtomic replaces path with data via a temporary file and rename.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".session-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
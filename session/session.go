@@ -0,0 +1,286 @@
+// Package session manages multi-turn chat conversations: it owns the system prompt and message history,
+// keeps the history within a token budget and tracks token usage.
+package session
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/tools"
+	"sync"
+	"time"
+)
+
+// Usage accumulates the token counts and timings reported by the server.
+type Usage struct {
+	Requests         int           `json:"requests"`           // Chat requests made.
+	PromptTokens     int           `json:"prompt_tokens"`      // Prompt tokens evaluated across all requests.
+	EvalTokens       int           `json:"eval_tokens"`        // Tokens generated across all requests.
+	LastPromptTokens int           `json:"last_prompt_tokens"` // Prompt size of the latest request, i.e. current context use.
+	PromptDuration   time.Duration `json:"prompt_duration"`    // Time spent evaluating prompts.
+	EvalDuration     time.Duration `json:"eval_duration"`      // Time spent generating.
+	TotalDuration    time.Duration `json:"total_duration"`     // Total server time, including model loads.
+}
+
+// Add records the usage reported by a response.
+func (u *Usage) Add(resp structures.ChatResponse) {
+	u.Requests++
+	u.PromptTokens += resp.PromptEvalCount
+	u.EvalTokens += resp.EvalCount
+	u.LastPromptTokens = resp.PromptEvalCount
+	u.PromptDuration += time.Duration(resp.PromptEvalDuration)
+	u.EvalDuration += time.Duration(resp.EvalDuration)
+	u.TotalDuration += time.Duration(resp.TotalDuration)
+}
+
+// TotalTokens returns prompt plus generated tokens.
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.EvalTokens
+}
+
+// Session is a chat conversation with a model. Methods are safe for concurrent use; sends are serialized.
+type Session struct {
+	Client    *client.OllamaClient
+	ID        string // Identifier used when the session is saved.
+	Store     Store  // Optional: The session is saved here after every successful send.
+	Model     string
+	System    string                        // Optional: System prompt, always sent first and never trimmed.
+	Messages  []structures.Message          // History, without the system prompt.
+	Options   structures.Options            // Options sent with every request.
+	KeepAlive *structures.KeepAlive         // Optional: Keep-alive sent with every request.
+	Tools     *tools.ToolRegistry           // Optional: Tools offered to the model; calls are executed automatically.
+	Budget    int                           // Prompt token budget; 0 disables trimming.
+	Strategy  Strategy                      // How the history is trimmed (DropOldest if nil).
+	Counter   TokenCounter                  // Token estimator (EstimateTokens if nil).
+	Usage     Usage                         // Accumulated token usage.
+	Metadata  map[string]string             // Optional: Labels saved with the session.
+	OnChunk   func(structures.ChatResponse) // Optional: Streams replies, receiving each chunk; unused when Tools is set.
+
+	mu      sync.Mutex
+	version int64
+	parent  string
+	created time.Time
+}
+
+// New creates a session with the model and system prompt (which may be empty).
+func New(c *client.OllamaClient, model, system string) *Session {
+	return &Session{Client: c, Model: model, System: system}
+}
+
+// Open loads the session id from store. The returned session saves back to store.
+func Open(ctx context.Context, c *client.OllamaClient, store Store, id string) (*Session, error) {
+	rec, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s := FromRecord(c, rec)
+	s.Store = store
+	return s, nil
+}
+
+// FromRecord creates a session from a stored record.
+func FromRecord(c *client.OllamaClient, rec *Record) *Session {
+	return &Session{
+		Client:    c,
+		ID:        rec.ID,
+		Model:     rec.Model,
+		System:    rec.System,
+		Messages:  append([]structures.Message(nil), rec.Messages...),
+		Options:   rec.Options,
+		KeepAlive: rec.KeepAlive,
+		Usage:     rec.Usage,
+		Metadata:  rec.Metadata,
+		version:   rec.Version,
+		parent:    rec.ParentID,
+		created:   rec.CreatedAt,
+	}
+}
+
+// Record returns a snapshot of the session for storage.
+func (s *Session) Record() *Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.record()
+}
+
+// Save stores the session in Store, assigning a random ID if it has none. It returns
+// utils.ErrVersionConflict if the stored session was changed by another writer since it was loaded.
+func (s *Session) Save(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(ctx)
+}
+
+// SetBudgetFromModel sets Budget to the model's context window minus reserve tokens kept free for the reply.
+// If reserve is not positive, a quarter of the window is reserved. Unless Options.NumCtx is already set, it is
+// set to the window, since the server would otherwise allocate its smaller default context.
+func (s *Session) SetBudgetFromModel(ctx context.Context, reserve int) error {
+	info, err := s.Client.ShowModelContext(ctx, structures.ShowModelRequest{Model: s.Model})
+	if err != nil {
+		return err
+	}
+	window := info.ContextWindow()
+	if s.Options.NumCtx != nil {
+		window = *s.Options.NumCtx
+	}
+	if window <= 0 {
+		return fmt.Errorf("model %s does not report a context length", s.Model)
+	}
+	if reserve <= 0 {
+		reserve = window / 4
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Options.NumCtx == nil {
+		s.Options.NumCtx = structures.Ptr(window)
+	}
+	s.Budget = window - reserve
+	return nil
+}
+
+// Send adds a user message and returns the model's reply, which is appended to the history.
+func (s *Session) Send(ctx context.Context, content string, images ...string) (*structures.ChatResponse, error) {
+	return s.SendMessage(ctx, structures.Message{Role: "user", Content: content, Images: images})
+}
+
+// SendMessage adds msg to the history, trims the history to the budget and asks the model for a reply.
+// If Tools is set, the tools the model calls are run and their results sent back until the model answers;
+// every assistant and tool message is appended to the history. On error the history is left unchanged.
+// If Store is set the session is then saved; a save error is returned along with the reply.
+func (s *Session) SendMessage(ctx context.Context, msg structures.Message) (*structures.ChatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := append(append([]structures.Message(nil), s.Messages...), msg)
+	history, err := s.fit(ctx, history)
+	if err != nil {
+		return nil, err
+	}
+
+	req := s.request(history)
+	if s.Tools != nil {
+		transcript, err := tools.NewRunner(s.Client, s.Tools).Run(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, step := range transcript.Steps {
+			s.Usage.Add(step.Response)
+		}
+		s.Messages = transcript.Messages[len(req.Messages)-len(history):]
+		return &transcript.Final, s.autosave(ctx)
+	}
+
+	req.Stream = s.OnChunk != nil
+	resp, err := s.Client.ChatContext(ctx, req, s.OnChunk)
+	if err != nil {
+		return nil, err
+	}
+	s.Usage.Add(*resp)
+	s.Messages = append(history, resp.Message)
+	return resp, s.autosave(ctx)
+}
+
+// autosave saves the session if it has a Store.
+func (s *Session) autosave(ctx context.Context) error {
+	if s.Store == nil {
+		return nil
+	}
+	return s.save(ctx)
+}
+
+func (s *Session) save(ctx context.Context) error {
+	if s.Store == nil {
+		return fmt.Errorf("session %s has no store", s.ID)
+	}
+	if s.ID == "" {
+		s.ID = NewID()
+	}
+	rec := s.record()
+	if err := s.Store.Save(ctx, rec); err != nil {
+		return err
+	}
+	s.version = rec.Version
+	s.created = rec.CreatedAt
+	return nil
+}
+
+func (s *Session) record() *Record {
+	return &Record{
+		ID:        s.ID,
+		Version:   s.version,
+		ParentID:  s.parent,
+		Model:     s.Model,
+		System:    s.System,
+		Messages:  append([]structures.Message(nil), s.Messages...),
+		Options:   s.Options,
+		KeepAlive: s.KeepAlive,
+		Usage:     s.Usage,
+		Metadata:  s.Metadata,
+		CreatedAt: s.created,
+	}
+}
+
+// Request returns the chat request for the current history, e.g. to send it elsewhere.
+func (s *Session) Request() structures.ChatRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.request(append([]structures.Message(nil), s.Messages...))
+}
+
+// Tokens returns the estimated prompt size of the current history, including the system prompt.
+func (s *Session) Tokens() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return countAll(s.request(s.Messages).Messages, s.counter())
+}
+
+// Reset clears the history and usage, keeping the system prompt and settings.
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Messages = nil
+	s.Usage = Usage{}
+}
+
+// fit trims history to the budget left after the system prompt.
+func (s *Session) fit(ctx context.Context, history []structures.Message) ([]structures.Message, error) {
+	if s.Budget <= 0 {
+		return history, nil
+	}
+	count := s.counter()
+	budget := s.Budget
+	if s.System != "" {
+		budget -= count(structures.Message{Role: "system", Content: s.System})
+	}
+	if countAll(history, count) <= budget {
+		return history, nil
+	}
+	strategy := s.Strategy
+	if strategy == nil {
+		strategy = DropOldest{}
+	}
+	return strategy.Fit(ctx, history, budget, count)
+}
+
+// request builds a chat request from the system prompt and history.
+func (s *Session) request(history []structures.Message) structures.ChatRequest {
+	messages := make([]structures.Message, 0, len(history)+1)
+	if s.System != "" {
+		messages = append(messages, structures.Message{Role: "system", Content: s.System})
+	}
+	return structures.ChatRequest{
+		Model:     s.Model,
+		Messages:  append(messages, history...),
+		Options:   s.Options,
+		KeepAlive: s.KeepAlive,
+	}
+}
+
+func (s *Session) counter() TokenCounter {
+	if s.Counter != nil {
+		return s.Counter
+	}
+	return EstimateTokens
+}
@@ -0,0 +1,222 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record is a stored chat session. Messages keep their tool calls and images, so a loaded record can be
+// replayed directly with ChatRequest.
+type Record struct {
+	ID        string                `json:"id"`
+	Version   int64                 `json:"version"`             // Incremented by every successful Save.
+	ParentID  string                `json:"parent_id,omitempty"` // Session this one was forked from.
+	Model     string                `json:"model"`
+	System    string                `json:"system,omitempty"`
+	Messages  []structures.Message  `json:"messages,omitempty"`
+	Options   structures.Options    `json:"options,omitempty"`
+	KeepAlive *structures.KeepAlive `json:"keep_alive,omitempty"`
+	Usage     Usage                 `json:"usage"`
+	Metadata  map[string]string     `json:"metadata,omitempty"` // Free-form labels (e.g., user or channel IDs).
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
+}
+
+// ChatRequest returns the request that replays the stored conversation.
+func (r *Record) ChatRequest() structures.ChatRequest {
+	messages := make([]structures.Message, 0, len(r.Messages)+1)
+	if r.System != "" {
+		messages = append(messages, structures.Message{Role: "system", Content: r.System})
+	}
+	return structures.ChatRequest{
+		Model:     r.Model,
+		Messages:  append(messages, r.Messages...),
+		Options:   r.Options,
+		KeepAlive: r.KeepAlive,
+	}
+}
+
+// Info summarizes a stored session for listings.
+type Info struct {
+	ID        string    `json:"id"`
+	ParentID  string    `json:"parent_id,omitempty"`
+	Model     string    `json:"model"`
+	Version   int64     `json:"version"`
+	Messages  int       `json:"messages"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store persists chat sessions.
+//
+// Save uses optimistic concurrency: rec.Version must equal the stored version (0 for a new session),
+// otherwise utils.ErrVersionConflict is returned and the caller should reload and retry. On success
+// rec.Version and rec.UpdatedAt are updated.
+type Store interface {
+	Save(ctx context.Context, rec *Record) error
+	Load(ctx context.Context, id string) (*Record, error) // utils.ErrSessionNotFound if missing.
+	List(ctx context.Context) ([]Info, error)             // Most recently updated first.
+	Delete(ctx context.Context, id string) error          // utils.ErrSessionNotFound if missing.
+}
+
+// idPattern restricts session IDs to characters that are safe in file names.
+var idPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,127}$`)
+
+// NewID returns a random session ID.
+func NewID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validateID rejects IDs that could escape a store's namespace.
+func validateID(id string) error {
+	if !idPattern.MatchString(id) {
+		return fmt.Errorf("invalid session ID %q", id)
+	}
+	return nil
+}
+
+// Fork copies the session id into a new session newID (a random ID if empty), keeping only the first keep
+// messages if keep is positive. The fork records id as its parent.
+func Fork(ctx context.Context, store Store, id, newID string, keep int) (*Record, error) {
+	rec, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if newID == "" {
+		newID = NewID()
+	}
+	if keep > 0 && keep < len(rec.Messages) {
+		rec.Messages = rec.Messages[:keep]
+	}
+	rec.ParentID = rec.ID
+	rec.ID = newID
+	rec.Version = 0
+	rec.CreatedAt = time.Time{}
+	if err := store.Save(ctx, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// MemoryStore keeps sessions in memory. Records are copied on the way in and out.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: map[string][]byte{}}
+}
+
+// Save implements Store.
+func (m *MemoryStore) Save(_ context.Context, rec *Record) error {
+	if err := validateID(rec.ID); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var current int64
+	if data, ok := m.records[rec.ID]; ok {
+		var stored Record
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return err
+		}
+		current = stored.Version
+	}
+	data, err := stamp(rec, current)
+	if err != nil {
+		return err
+	}
+	m.records[rec.ID] = data
+	return nil
+}
+
+// Load implements Store.
+func (m *MemoryStore) Load(_ context.Context, id string) (*Record, error) {
+	m.mu.Lock()
+	data, ok := m.records[id]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", utils.ErrSessionNotFound, id)
+	}
+	rec := &Record{}
+	return rec, json.Unmarshal(data, rec)
+}
+
+// List implements Store.
+func (m *MemoryStore) List(ctx context.Context) ([]Info, error) {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.records))
+	for id := range m.records {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	infos := make([]Info, 0, len(ids))
+	for _, id := range ids {
+		rec, err := m.Load(ctx, id)
+		if err != nil {
+			continue // Deleted since listing
+		}
+		infos = append(infos, rec.info())
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.records[id]; !ok {
+		return fmt.Errorf("%w: %s", utils.ErrSessionNotFound, id)
+	}
+	delete(m.records, id)
+	return nil
+}
+
+// stamp checks rec against the stored version, advances its version and timestamps, and encodes it.
+func stamp(rec *Record, current int64) ([]byte, error) {
+	if rec.Version != current {
+		return nil, fmt.Errorf("%w: %s is at version %d, not %d", utils.ErrVersionConflict, rec.ID, current, rec.Version)
+	}
+	now := time.Now().UTC()
+	next := *rec
+	next.Version++
+	next.UpdatedAt = now
+	if next.CreatedAt.IsZero() {
+		next.CreatedAt = now
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+	*rec = next
+	return data, nil
+}
+
+func (r *Record) info() Info {
+	return Info{ID: r.ID, ParentID: r.ParentID, Model: r.Model, Version: r.Version, Messages: len(r.Messages), UpdatedAt: r.UpdatedAt}
+}
+
+// sortInfos orders infos by most recent update, then ID.
+func sortInfos(infos []Info) {
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].UpdatedAt.Equal(infos[j].UpdatedAt) {
+			return infos[i].UpdatedAt.After(infos[j].UpdatedAt)
+		}
+		return infos[i].ID < infos[j].ID
+	})
+}
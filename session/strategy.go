@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/structures"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenCounter estimates how many prompt tokens a message takes.
+type TokenCounter func(structures.Message) int
+
+// Strategy shortens a history so that it fits within budget tokens. The last message (the one about to be
+// answered) must be kept.
+type Strategy interface {
+	Fit(ctx context.Context, history []structures.Message, budget int, count TokenCounter) ([]structures.Message, error)
+}
+
+// EstimateTokens is the default TokenCounter: roughly four characters per token, plus a small per-message
+// overhead for the chat template and a fixed cost per image.
+func EstimateTokens(msg structures.Message) int {
+	tokens := 4 + (utf8.RuneCountInString(msg.Content)+3)/4
+	tokens += 768 * len(msg.Images)
+	for _, call := range msg.ToolCalls {
+		tokens += 8 + (len(call.Function.Name)+len(fmt.Sprint(call.Function.Arguments))+3)/4
+	}
+	return tokens
+}
+
+// countAll sums the tokens of messages.
+func countAll(messages []structures.Message, count TokenCounter) int {
+	total := 0
+	for _, msg := range messages {
+		total += count(msg)
+	}
+	return total
+}
+
+// DropOldest removes the oldest messages until the history fits.
+type DropOldest struct{}
+
+// Fit implements Strategy.
+func (DropOldest) Fit(_ context.Context, history []structures.Message, budget int, count TokenCounter) ([]structures.Message, error) {
+	return dropOldest(nil, history, budget, count), nil
+}
+
+// PinFirst keeps the first user message (which often states the task) and drops the oldest messages after it.
+type PinFirst struct{}
+
+// Fit implements Strategy.
+func (PinFirst) Fit(_ context.Context, history []structures.Message, budget int, count TokenCounter) ([]structures.Message, error) {
+	for i, msg := range history {
+		if msg.Role == "user" && i < len(history)-1 {
+			pinned := append([]structures.Message(nil), history[:i+1]...)
+			return dropOldest(pinned, history[i+1:], budget, count), nil
+		}
+	}
+	return dropOldest(nil, history, budget, count), nil
+}
+
+// dropOldest keeps pinned and as many of the most recent messages of rest as fit, never splitting an
+// assistant message from the tool results that answer it.
+func dropOldest(pinned, rest []structures.Message, budget int, count TokenCounter) []structures.Message {
+	used := countAll(pinned, count) + countAll(rest, count)
+	start := 0
+	for used > budget && start < len(rest)-1 {
+		used -= count(rest[start])
+		start++
+		// A tool result without the assistant message that requested it is meaningless
+		for start < len(rest)-1 && rest[start].Role == "tool" {
+			used -= count(rest[start])
+			start++
+		}
+	}
+	return append(pinned, rest[start:]...)
+}
+
+// Summarize replaces the oldest messages with a model-written summary when the history does not fit,
+// keeping the most recent messages verbatim. If the result still does not fit, the oldest kept messages
+// are dropped.
+type Summarize struct {
+	Client *client.OllamaClient
+	Model  string // Model used to write the summary.
+	Keep   int    // Recent messages kept verbatim (4 if zero).
+	Prompt string // Optional: Instruction for the summary (a generic one if empty).
+}
+
+// Fit implements Strategy.
+func (s Summarize) Fit(ctx context.Context, history []structures.Message, budget int, count TokenCounter) ([]structures.Message, error) {
+	if countAll(history, count) <= budget {
+		return history, nil
+	}
+	keep := s.Keep
+	if keep <= 0 {
+		keep = 4
+	}
+	if keep >= len(history) {
+		keep = 1
+	}
+	split := len(history) - keep
+	for split > 0 && history[split].Role == "tool" {
+		split-- // Keep tool results together with the call that produced them
+	}
+	if split == 0 {
+		return dropOldest(nil, history, budget, count), nil
+	}
+
+	summary, err := s.summarize(ctx, history[:split])
+	if err != nil {
+		return nil, err
+	}
+	pinned := []structures.Message{{Role: "system", Content: "Summary of the earlier conversation:\n" + summary}}
+	return dropOldest(pinned, history[split:], budget, count), nil
+}
+
+// summarize asks the model for a summary of messages.
+func (s Summarize) summarize(ctx context.Context, messages []structures.Message) (string, error) {
+	prompt := s.Prompt
+	if prompt == "" {
+		prompt = "Summarize the following conversation in a few sentences. Keep names, facts, decisions and open questions."
+	}
+	var transcript strings.Builder
+	for _, msg := range messages {
+		fmt.Fprintf(&transcript, "%s: %s\n", msg.Role, msg.Content)
+	}
+	resp, err := s.Client.ChatContext(ctx, structures.ChatRequest{
+		Model: s.Model,
+		Messages: []structures.Message{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: transcript.String()},
+		},
+	}, nil)
+	if err != nil {
+		return "", fmt.Errorf("summarizing history: %w", err)
+	}
+	return strings.TrimSpace(resp.Message.Content), nil
+}
@@ -0,0 +1,249 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/structures"
+	"time"
+)
+
+// Node is a message in a conversation tree.
+type Node struct {
+	ID        string             `json:"id"`
+	ParentID  string             `json:"parent_id,omitempty"` // Empty for the first message of a conversation.
+	Children  []string           `json:"children,omitempty"`  // Continuations, oldest first.
+	Message   structures.Message `json:"message"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// Tree is a conversation with alternative branches: edited messages and regenerated replies are kept as
+// siblings instead of overwriting each other. Head is the checked-out node; new messages continue from it.
+// A Tree is not safe for concurrent use.
+type Tree struct {
+	System string           `json:"system,omitempty"` // System prompt sent before every path.
+	Nodes  map[string]*Node `json:"nodes"`
+	Roots  []string         `json:"roots,omitempty"` // First messages of each top-level branch.
+	Head   string           `json:"head,omitempty"`  // Checked-out node; empty before the first message.
+}
+
+// TreeDiff compares two paths through a tree.
+type TreeDiff struct {
+	Common []structures.Message // Messages shared by both paths.
+	A      []structures.Message // Messages only on the first path.
+	B      []structures.Message // Messages only on the second path.
+	ForkID string               // Last shared node (empty if the paths share nothing).
+}
+
+// NewTree creates an empty conversation tree.
+func NewTree(system string) *Tree {
+	return &Tree{System: system, Nodes: map[string]*Node{}}
+}
+
+// Node returns the node with the given ID.
+func (t *Tree) Node(id string) (*Node, error) {
+	node, ok := t.Nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("conversation node %q not found", id)
+	}
+	return node, nil
+}
+
+// Append adds msg after Head and checks it out.
+func (t *Tree) Append(msg structures.Message) *Node {
+	node, _ := t.add(t.Head, msg)
+	return node
+}
+
+// Branch adds msg as a new child of the node parentID (the start of the conversation if empty) and checks
+// it out. Existing children of parentID are kept as alternative branches.
+func (t *Tree) Branch(parentID string, msg structures.Message) (*Node, error) {
+	return t.add(parentID, msg)
+}
+
+// Edit creates an alternative to the node id with new content (keeping its role) and checks it out.
+func (t *Tree) Edit(id, content string) (*Node, error) {
+	node, err := t.Node(id)
+	if err != nil {
+		return nil, err
+	}
+	msg := node.Message
+	msg.Content = content
+	return t.add(node.ParentID, msg)
+}
+
+// Checkout moves Head to the node id.
+func (t *Tree) Checkout(id string) error {
+	if _, err := t.Node(id); err != nil {
+		return err
+	}
+	t.Head = id
+	return nil
+}
+
+// Siblings returns the alternatives to the node id, including itself, oldest first.
+func (t *Tree) Siblings(id string) ([]*Node, error) {
+	node, err := t.Node(id)
+	if err != nil {
+		return nil, err
+	}
+	ids := t.Roots
+	if node.ParentID != "" {
+		ids = t.Nodes[node.ParentID].Children
+	}
+	siblings := make([]*Node, 0, len(ids))
+	for _, sibling := range ids {
+		siblings = append(siblings, t.Nodes[sibling])
+	}
+	return siblings, nil
+}
+
+// Leaves returns the tips of every branch, in creation order.
+func (t *Tree) Leaves() []*Node {
+	var leaves []*Node
+	var walk func(ids []string)
+	walk = func(ids []string) {
+		for _, id := range ids {
+			node := t.Nodes[id]
+			if len(node.Children) == 0 {
+				leaves = append(leaves, node)
+			}
+			walk(node.Children)
+		}
+	}
+	walk(t.Roots)
+	return leaves
+}
+
+// Path returns the nodes from the start of the conversation to id.
+func (t *Tree) Path(id string) ([]*Node, error) {
+	var path []*Node
+	for id != "" {
+		node, err := t.Node(id)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, node)
+		id = node.ParentID
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, nil
+}
+
+// Transcript exports the path to id as a linear list of messages, starting with the system prompt.
+func (t *Tree) Transcript(id string) ([]structures.Message, error) {
+	path, err := t.Path(id)
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]structures.Message, 0, len(path)+1)
+	if t.System != "" {
+		messages = append(messages, structures.Message{Role: "system", Content: t.System})
+	}
+	for _, node := range path {
+		messages = append(messages, node.Message)
+	}
+	return messages, nil
+}
+
+// Request returns req with its messages set to the path to id, ready to send.
+func (t *Tree) Request(req structures.ChatRequest, id string) (structures.ChatRequest, error) {
+	messages, err := t.Transcript(id)
+	req.Messages = messages
+	return req, err
+}
+
+// Send asks the model to continue the checked-out path with msg, then appends msg and the reply after Head.
+// req supplies the model and options; its messages are replaced. On error the tree is left unchanged.
+func (t *Tree) Send(ctx context.Context, c *client.OllamaClient, req structures.ChatRequest, msg structures.Message) (*Node, error) {
+	req, err := t.Request(req, t.Head)
+	if err != nil {
+		return nil, err
+	}
+	req.Messages = append(req.Messages, msg)
+	resp, err := c.ChatContext(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	t.Append(msg)
+	return t.Append(resp.Message), nil
+}
+
+// Regenerate asks the model for an alternative to the assistant reply at Head, adding it as a sibling and
+// checking it out. If Head is not an assistant message, a reply to Head is generated instead.
+func (t *Tree) Regenerate(ctx context.Context, c *client.OllamaClient, req structures.ChatRequest) (*Node, error) {
+	head, err := t.Node(t.Head)
+	if err != nil {
+		return nil, err
+	}
+	from := head.ID
+	if head.Message.Role == "assistant" {
+		from = head.ParentID
+	}
+	return t.reply(ctx, c, req, from)
+}
+
+// reply generates the model's answer to the path ending at id and adds it as a child of id.
+func (t *Tree) reply(ctx context.Context, c *client.OllamaClient, req structures.ChatRequest, id string) (*Node, error) {
+	req, err := t.Request(req, id)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.ChatContext(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return t.add(id, resp.Message)
+}
+
+// Diff compares the paths to a and b.
+func (t *Tree) Diff(a, b string) (*TreeDiff, error) {
+	pathA, err := t.Path(a)
+	if err != nil {
+		return nil, err
+	}
+	pathB, err := t.Path(b)
+	if err != nil {
+		return nil, err
+	}
+	diff := &TreeDiff{}
+	shared := 0
+	for shared < len(pathA) && shared < len(pathB) && pathA[shared].ID == pathB[shared].ID {
+		diff.Common = append(diff.Common, pathA[shared].Message)
+		diff.ForkID = pathA[shared].ID
+		shared++
+	}
+	for _, node := range pathA[shared:] {
+		diff.A = append(diff.A, node.Message)
+	}
+	for _, node := range pathB[shared:] {
+		diff.B = append(diff.B, node.Message)
+	}
+	return diff, nil
+}
+
+// add creates a child of parentID (a root if empty) and checks it out.
+func (t *Tree) add(parentID string, msg structures.Message) (*Node, error) {
+	if t.Nodes == nil {
+		t.Nodes = map[string]*Node{}
+	}
+	var parent *Node
+	if parentID != "" {
+		var err error
+		if parent, err = t.Node(parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	node := &Node{ID: fmt.Sprintf("n%d", len(t.Nodes)+1), ParentID: parentID, Message: msg, CreatedAt: time.Now().UTC()}
+	t.Nodes[node.ID] = node
+	if parent != nil {
+		parent.Children = append(parent.Children, node.ID)
+	} else {
+		t.Roots = append(t.Roots, node.ID)
+	}
+	t.Head = node.ID
+	return node, nil
+}
@@ -0,0 +1,69 @@
+package structures
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// KeepAlive controls how long a model stays loaded after a request. Zero unloads the model immediately and
+// any negative value (see KeepAliveForever) keeps it loaded indefinitely.
+type KeepAlive time.Duration
+
+// KeepAliveForever keeps a model loaded until it is explicitly unloaded.
+const KeepAliveForever KeepAlive = -1
+
+// KeepAliveFor returns a keep-alive of d, ready to set on a request.
+func KeepAliveFor(d time.Duration) *KeepAlive {
+	k := KeepAlive(d)
+	return &k
+}
+
+// Duration returns the keep-alive as a time.Duration (negative for forever).
+func (k KeepAlive) Duration() time.Duration {
+	return time.Duration(k)
+}
+
+// Forever reports whether the model is kept loaded indefinitely.
+func (k KeepAlive) Forever() bool {
+	return k < 0
+}
+
+func (k KeepAlive) String() string {
+	if k.Forever() {
+		return "forever"
+	}
+	return time.Duration(k).String()
+}
+
+// MarshalJSON encodes forever as -1 and other values as a duration string (e.g., "5m0s").
+func (k KeepAlive) MarshalJSON() ([]byte, error) {
+	if k.Forever() {
+		return []byte("-1"), nil
+	}
+	return json.Marshal(time.Duration(k).String())
+}
+
+// UnmarshalJSON accepts a duration string or a number of seconds, as the server does.
+func (k *KeepAlive) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch v := v.(type) {
+	case float64:
+		*k = KeepAlive(v * float64(time.Second))
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid keep_alive %q: %w", v, err)
+		}
+		*k = KeepAlive(d)
+	default:
+		return fmt.Errorf("invalid keep_alive %s", data)
+	}
+	if *k < 0 {
+		*k = KeepAliveForever
+	}
+	return nil
+}
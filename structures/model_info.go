@@ -0,0 +1,172 @@
+package structures
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Model capabilities reported by /api/show.
+const (
+	CapabilityCompletion = "completion"
+	CapabilityTools      = "tools"
+	CapabilityVision     = "vision"
+	CapabilityEmbedding  = "embedding"
+	CapabilityInsert     = "insert"
+	CapabilityThinking   = "thinking"
+)
+
+// ModelMetadata is the GGUF metadata returned as model_info, keyed like "general.architecture" or
+// "llama.context_length". Architecture-specific keys are prefixed with the architecture name.
+type ModelMetadata map[string]interface{}
+
+// String returns the string value stored under key.
+func (m ModelMetadata) String(key string) (string, bool) {
+	v, ok := m[key].(string)
+	return v, ok
+}
+
+// Int returns the integer value stored under key.
+func (m ModelMetadata) Int(key string) (int64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	}
+	return 0, false
+}
+
+// Float returns the numeric value stored under key.
+func (m ModelMetadata) Float(key string) (float64, bool) {
+	switch v := m[key].(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// Architecture returns the model architecture (e.g., "llama").
+func (m ModelMetadata) Architecture() string {
+	v, _ := m.String("general.architecture")
+	return v
+}
+
+// ArchInt returns an architecture-specific integer, e.g. ArchInt("context_length") reads "llama.context_length".
+func (m ModelMetadata) ArchInt(name string) (int64, bool) {
+	return m.Int(m.Architecture() + "." + name)
+}
+
+// ParameterCount returns the number of model parameters.
+func (m ModelMetadata) ParameterCount() int64 {
+	v, _ := m.Int("general.parameter_count")
+	return v
+}
+
+// FileType returns the GGUF file type (quantization) identifier.
+func (m ModelMetadata) FileType() int64 {
+	v, _ := m.Int("general.file_type")
+	return v
+}
+
+// ContextLength returns the maximum context length the model was trained with.
+func (m ModelMetadata) ContextLength() int64 {
+	v, _ := m.ArchInt("context_length")
+	return v
+}
+
+// EmbeddingLength returns the size of the model's embedding vectors.
+func (m ModelMetadata) EmbeddingLength() int64 {
+	v, _ := m.ArchInt("embedding_length")
+	return v
+}
+
+// BlockCount returns the number of transformer blocks (layers).
+func (m ModelMetadata) BlockCount() int64 {
+	v, _ := m.ArchInt("block_count")
+	return v
+}
+
+// HeadCount returns the number of attention heads.
+func (m ModelMetadata) HeadCount() int64 {
+	v, _ := m.ArchInt("attention.head_count")
+	return v
+}
+
+// HeadCountKV returns the number of key/value attention heads.
+func (m ModelMetadata) HeadCountKV() int64 {
+	v, _ := m.ArchInt("attention.head_count_kv")
+	return v
+}
+
+// HasCapability reports whether the model lists capability (e.g., CapabilityTools).
+func (r *ShowModelResponse) HasCapability(capability string) bool {
+	for _, c := range r.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsTools reports whether the model accepts tool definitions.
+func (r *ShowModelResponse) SupportsTools() bool {
+	return r.HasCapability(CapabilityTools)
+}
+
+// SupportsVision reports whether the model accepts images. Older servers that do not report capabilities
+// are covered by checking for a vision projector.
+func (r *ShowModelResponse) SupportsVision() bool {
+	return r.HasCapability(CapabilityVision) || len(r.ProjectorInfo) > 0
+}
+
+// SupportsEmbedding reports whether the model can generate embeddings.
+func (r *ShowModelResponse) SupportsEmbedding() bool {
+	return r.HasCapability(CapabilityEmbedding)
+}
+
+// SupportsThinking reports whether the model can emit separate reasoning output.
+func (r *ShowModelResponse) SupportsThinking() bool {
+	return r.HasCapability(CapabilityThinking)
+}
+
+// ContextWindow returns the maximum usable context: the model's num_ctx parameter if set, otherwise the
+// trained context length from its metadata. The latter is usually more than the server allocates by default,
+// so requests only get it with Options.NumCtx set. It returns 0 if neither is known.
+func (r *ShowModelResponse) ContextWindow() int {
+	if values := r.ParameterValues()["num_ctx"]; len(values) > 0 {
+		if n, err := strconv.Atoi(values[len(values)-1]); err == nil {
+			return n
+		}
+	}
+	return int(r.ModelInfo.ContextLength())
+}
+
+// ParameterValues parses Parameters into values by key. Keys such as stop may have several values.
+func (r *ShowModelResponse) ParameterValues() map[string][]string {
+	values := map[string][]string{}
+	for _, line := range strings.Split(r.Parameters, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		values[key] = append(values[key], value)
+	}
+	return values
+}
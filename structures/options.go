@@ -1,20 +1,296 @@
 package structures
 
+import (
+	"fmt"
+	"github.com/SamyRai/ollama-go/utils"
+	"strings"
+)
+
 // Options defines customizable parameters for model behavior.
+// Fields are pointers so that zero values (e.g., temperature 0) can be sent explicitly;
+// nil fields are omitted and the server default applies.
 type Options struct {
-	Temperature      float64 `json:"temperature,omitempty"`       // Controls creativity vs. coherence.
-	TopP             float64 `json:"top_p,omitempty"`             // Nucleus sampling parameter.
-	TopK             int     `json:"top_k,omitempty"`             // Limits highest probability tokens.
-	Mirostat         int     `json:"mirostat,omitempty"`          // Mirostat sampling mode.
-	MirostatTau      float64 `json:"mirostat_tau,omitempty"`      // Target surprise value for Mirostat.
-	MirostatEta      float64 `json:"mirostat_eta,omitempty"`      // Learning rate for Mirostat.
-	RepeatPenalty    float64 `json:"repeat_penalty,omitempty"`    // Penalizes repeated tokens.
-	RepeatLastN      int     `json:"repeat_last_n,omitempty"`     // Tokens considered for repetition penalty.
-	FrequencyPenalty float64 `json:"frequency_penalty,omitempty"` // Penalizes frequent tokens.
-	PresencePenalty  float64 `json:"presence_penalty,omitempty"`  // Penalizes existing tokens in context.
-	TFS              float64 `json:"tfs,omitempty"`               // Tail Free Sampling parameter.
-	TopA             float64 `json:"top_a,omitempty"`             // Alternative sampling parameter.
-	TypicalP         float64 `json:"typical_p,omitempty"`         // Typical probability threshold.
-	Grammar          string  `json:"grammar,omitempty"`           // Enforces specific grammar on output.
-	// Additional parameters may be added here as needed.
+	// Runtime parameters (applied when the model is loaded).
+	NumCtx    *int  `json:"num_ctx,omitempty"`    // Context window size in tokens.
+	NumBatch  *int  `json:"num_batch,omitempty"`  // Prompt processing batch size.
+	NumGPU    *int  `json:"num_gpu,omitempty"`    // Number of layers to offload to the GPU (-1 for all).
+	MainGPU   *int  `json:"main_gpu,omitempty"`   // GPU used for small tensors when splitting across GPUs.
+	LowVRAM   *bool `json:"low_vram,omitempty"`   // Reduce VRAM usage at the cost of speed.
+	UseMMap   *bool `json:"use_mmap,omitempty"`   // Memory-map the model weights.
+	UseMLock  *bool `json:"use_mlock,omitempty"`  // Lock the model weights in RAM.
+	NumThread *int  `json:"num_thread,omitempty"` // CPU threads used for generation.
+	NUMA      *bool `json:"numa,omitempty"`       // Enable NUMA support.
+
+	// Prediction parameters (applied per request).
+	NumKeep          *int     `json:"num_keep,omitempty"`          // Prompt tokens kept when the context is truncated.
+	Seed             *int     `json:"seed,omitempty"`              // Random seed for reproducible output.
+	NumPredict       *int     `json:"num_predict,omitempty"`       // Maximum tokens to generate (-1 infinite, -2 fill context).
+	Temperature      *float64 `json:"temperature,omitempty"`       // Controls creativity vs. coherence.
+	TopP             *float64 `json:"top_p,omitempty"`             // Nucleus sampling parameter.
+	TopK             *int     `json:"top_k,omitempty"`             // Limits highest probability tokens.
+	MinP             *float64 `json:"min_p,omitempty"`             // Minimum probability relative to the most likely token.
+	TypicalP         *float64 `json:"typical_p,omitempty"`         // Typical probability threshold.
+	Mirostat         *int     `json:"mirostat,omitempty"`          // Mirostat sampling mode (0 off, 1 or 2).
+	MirostatTau      *float64 `json:"mirostat_tau,omitempty"`      // Target surprise value for Mirostat.
+	MirostatEta      *float64 `json:"mirostat_eta,omitempty"`      // Learning rate for Mirostat.
+	RepeatPenalty    *float64 `json:"repeat_penalty,omitempty"`    // Penalizes repeated tokens.
+	RepeatLastN      *int     `json:"repeat_last_n,omitempty"`     // Tokens considered for repetition penalty (-1 for num_ctx).
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"` // Penalizes frequent tokens.
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`  // Penalizes existing tokens in context.
+	PenalizeNewline  *bool    `json:"penalize_newline,omitempty"`  // Apply the repetition penalty to newlines.
+	TFS              *float64 `json:"tfs_z,omitempty"`             // Tail Free Sampling parameter.
+	TopA             *float64 `json:"top_a,omitempty"`             // Alternative sampling parameter.
+	Grammar          *string  `json:"grammar,omitempty"`           // Enforces specific grammar on output.
+	Stop             []string `json:"stop,omitempty"`              // Sequences that stop generation.
+}
+
+// Ptr returns a pointer to v, for setting Options fields inline.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Validate checks option values against the ranges the server accepts.
+func (o Options) Validate() error {
+	var problems []string
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+	intAtLeast := func(name string, v *int, min int) {
+		if v != nil {
+			check(*v >= min, "%s must be >= %d, got %d", name, min, *v)
+		}
+	}
+	floatBetween := func(name string, v *float64, min, max float64) {
+		if v != nil {
+			check(*v >= min && *v <= max, "%s must be between %g and %g, got %g", name, min, max, *v)
+		}
+	}
+	floatAtLeast := func(name string, v *float64, min float64) {
+		if v != nil {
+			check(*v >= min, "%s must be >= %g, got %g", name, min, *v)
+		}
+	}
+
+	intAtLeast("num_ctx", o.NumCtx, 1)
+	intAtLeast("num_batch", o.NumBatch, 1)
+	intAtLeast("num_gpu", o.NumGPU, -1)
+	intAtLeast("main_gpu", o.MainGPU, 0)
+	intAtLeast("num_thread", o.NumThread, 0)
+	intAtLeast("num_keep", o.NumKeep, -1)
+	intAtLeast("num_predict", o.NumPredict, -2)
+	intAtLeast("top_k", o.TopK, 0)
+	intAtLeast("repeat_last_n", o.RepeatLastN, -1)
+	floatAtLeast("temperature", o.Temperature, 0)
+	floatBetween("top_p", o.TopP, 0, 1)
+	floatBetween("min_p", o.MinP, 0, 1)
+	floatBetween("typical_p", o.TypicalP, 0, 1)
+	floatAtLeast("mirostat_tau", o.MirostatTau, 0)
+	floatAtLeast("mirostat_eta", o.MirostatEta, 0)
+	floatAtLeast("repeat_penalty", o.RepeatPenalty, 0)
+	floatBetween("frequency_penalty", o.FrequencyPenalty, -2, 2)
+	floatBetween("presence_penalty", o.PresencePenalty, -2, 2)
+	floatAtLeast("tfs_z", o.TFS, 0)
+	floatAtLeast("top_a", o.TopA, 0)
+	if o.Mirostat != nil {
+		check(*o.Mirostat >= 0 && *o.Mirostat <= 2, "mirostat must be 0, 1 or 2, got %d", *o.Mirostat)
+	}
+	for _, stop := range o.Stop {
+		check(stop != "", "stop sequences must not be empty")
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", utils.ErrInvalidOptions, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// OptionsBuilder assembles Options fluently.
+type OptionsBuilder struct {
+	opts Options
+}
+
+// NewOptions starts building a set of Options.
+func NewOptions() *OptionsBuilder {
+	return &OptionsBuilder{}
+}
+
+// Build validates and returns the assembled Options.
+func (b *OptionsBuilder) Build() (Options, error) {
+	return b.opts, b.opts.Validate()
+}
+
+// NumCtx sets the context window size.
+func (b *OptionsBuilder) NumCtx(v int) *OptionsBuilder {
+	b.opts.NumCtx = &v
+	return b
+}
+
+// NumBatch sets the prompt processing batch size.
+func (b *OptionsBuilder) NumBatch(v int) *OptionsBuilder {
+	b.opts.NumBatch = &v
+	return b
+}
+
+// NumGPU sets the number of layers offloaded to the GPU.
+func (b *OptionsBuilder) NumGPU(v int) *OptionsBuilder {
+	b.opts.NumGPU = &v
+	return b
+}
+
+// MainGPU sets the GPU used for small tensors.
+func (b *OptionsBuilder) MainGPU(v int) *OptionsBuilder {
+	b.opts.MainGPU = &v
+	return b
+}
+
+// LowVRAM enables or disables low-VRAM mode.
+func (b *OptionsBuilder) LowVRAM(v bool) *OptionsBuilder {
+	b.opts.LowVRAM = &v
+	return b
+}
+
+// UseMMap enables or disables memory-mapping the weights.
+func (b *OptionsBuilder) UseMMap(v bool) *OptionsBuilder {
+	b.opts.UseMMap = &v
+	return b
+}
+
+// UseMLock enables or disables locking the weights in RAM.
+func (b *OptionsBuilder) UseMLock(v bool) *OptionsBuilder {
+	b.opts.UseMLock = &v
+	return b
+}
+
+// NumThread sets the number of CPU threads.
+func (b *OptionsBuilder) NumThread(v int) *OptionsBuilder {
+	b.opts.NumThread = &v
+	return b
+}
+
+// NUMA enables or disables NUMA support.
+func (b *OptionsBuilder) NUMA(v bool) *OptionsBuilder {
+	b.opts.NUMA = &v
+	return b
+}
+
+// NumKeep sets the prompt tokens kept on truncation.
+func (b *OptionsBuilder) NumKeep(v int) *OptionsBuilder {
+	b.opts.NumKeep = &v
+	return b
+}
+
+// Seed sets the random seed.
+func (b *OptionsBuilder) Seed(v int) *OptionsBuilder {
+	b.opts.Seed = &v
+	return b
+}
+
+// NumPredict sets the maximum number of tokens to generate.
+func (b *OptionsBuilder) NumPredict(v int) *OptionsBuilder {
+	b.opts.NumPredict = &v
+	return b
+}
+
+// Temperature sets the sampling temperature.
+func (b *OptionsBuilder) Temperature(v float64) *OptionsBuilder {
+	b.opts.Temperature = &v
+	return b
+}
+
+// TopP sets the nucleus sampling threshold.
+func (b *OptionsBuilder) TopP(v float64) *OptionsBuilder {
+	b.opts.TopP = &v
+	return b
+}
+
+// TopK sets the number of highest probability tokens considered.
+func (b *OptionsBuilder) TopK(v int) *OptionsBuilder {
+	b.opts.TopK = &v
+	return b
+}
+
+// MinP sets the minimum relative token probability.
+func (b *OptionsBuilder) MinP(v float64) *OptionsBuilder {
+	b.opts.MinP = &v
+	return b
+}
+
+// TypicalP sets the typical probability threshold.
+func (b *OptionsBuilder) TypicalP(v float64) *OptionsBuilder {
+	b.opts.TypicalP = &v
+	return b
+}
+
+// Mirostat sets the Mirostat sampling mode.
+func (b *OptionsBuilder) Mirostat(v int) *OptionsBuilder {
+	b.opts.Mirostat = &v
+	return b
+}
+
+// MirostatTau sets the Mirostat target surprise value.
+func (b *OptionsBuilder) MirostatTau(v float64) *OptionsBuilder {
+	b.opts.MirostatTau = &v
+	return b
+}
+
+// MirostatEta sets the Mirostat learning rate.
+func (b *OptionsBuilder) MirostatEta(v float64) *OptionsBuilder {
+	b.opts.MirostatEta = &v
+	return b
+}
+
+// RepeatPenalty sets the repetition penalty.
+func (b *OptionsBuilder) RepeatPenalty(v float64) *OptionsBuilder {
+	b.opts.RepeatPenalty = &v
+	return b
+}
+
+// RepeatLastN sets how many tokens the repetition penalty looks back.
+func (b *OptionsBuilder) RepeatLastN(v int) *OptionsBuilder {
+	b.opts.RepeatLastN = &v
+	return b
+}
+
+// FrequencyPenalty sets the frequency penalty.
+func (b *OptionsBuilder) FrequencyPenalty(v float64) *OptionsBuilder {
+	b.opts.FrequencyPenalty = &v
+	return b
+}
+
+// PresencePenalty sets the presence penalty.
+func (b *OptionsBuilder) PresencePenalty(v float64) *OptionsBuilder {
+	b.opts.PresencePenalty = &v
+	return b
+}
+
+// PenalizeNewline sets whether newlines are subject to the repetition penalty.
+func (b *OptionsBuilder) PenalizeNewline(v bool) *OptionsBuilder {
+	b.opts.PenalizeNewline = &v
+	return b
+}
+
+// TFS sets the tail free sampling parameter.
+func (b *OptionsBuilder) TFS(v float64) *OptionsBuilder {
+	b.opts.TFS = &v
+	return b
+}
+
+// TopA sets the top-a sampling parameter.
+func (b *OptionsBuilder) TopA(v float64) *OptionsBuilder {
+	b.opts.TopA = &v
+	return b
+}
+
+// Grammar sets the grammar the output must follow.
+func (b *OptionsBuilder) Grammar(v string) *OptionsBuilder {
+	b.opts.Grammar = &v
+	return b
+}
+
+// Stop appends stop sequences.
+func (b *OptionsBuilder) Stop(v ...string) *OptionsBuilder {
+	b.opts.Stop = append(b.opts.Stop, v...)
+	return b
 }
@@ -10,17 +10,23 @@ type Message struct {
 	Content   string     `json:"content"`              // Message content.
 	Images    []string   `json:"images,omitempty"`     // Optional: Base64-encoded images (for multimodal models).
 	ToolCalls []ToolCall `json:"tool_calls,omitempty"` // Optional: Tool calls made by the model.
+	ToolName  string     `json:"tool_name,omitempty"`  // Optional: Name of the tool whose result this "tool" message carries.
 }
 
 // ChatRequest is used to engage in a chat conversation with a model.
 type ChatRequest struct {
-	Model     string    `json:"model"`                // Required: Name of the model to use.
-	Messages  []Message `json:"messages"`             // Required: Chat history messages.
-	Tools     []Tool    `json:"tools,omitempty"`      // Optional: Available tools.
-	Format    string    `json:"format,omitempty"`     // Optional: Response format.
-	Options   Options   `json:"options,omitempty"`    // Optional: Additional options.
-	Stream    bool      `json:"stream,omitempty"`     // Optional: Whether to stream responses.
-	KeepAlive string    `json:"keep_alive,omitempty"` // Optional: Duration to keep model in memory.
+	Model     string      `json:"model"`                // Required: Name of the model to use.
+	Messages  []Message   `json:"messages"`             // Required: Chat history messages.
+	Tools     []Tool      `json:"tools,omitempty"`      // Optional: Available tools.
+	Format    interface{} `json:"format,omitempty"`     // Optional: "json" or a JSON schema (e.g., *jsonschema.Schema).
+	Options   Options     `json:"options,omitempty"`    // Optional: Additional options.
+	Stream    bool        `json:"stream"`               // Whether to stream responses (always sent: the server streams by default).
+	KeepAlive *KeepAlive  `json:"keep_alive,omitempty"` // Optional: How long to keep the model in memory.
+}
+
+// Validate checks the request's options before it is sent.
+func (r ChatRequest) Validate() error {
+	return r.Options.Validate()
 }
 
 // =========================
@@ -29,12 +35,17 @@ type ChatRequest struct {
 
 // EmbeddingRequest is used to generate embeddings.
 type EmbeddingRequest struct {
-	Model     string   `json:"model"`             // Model name.
-	Input     []string `json:"input"`             // Input text(s).
-	Truncate  bool     `json:"truncate"`          // Whether to truncate input if needed.
-	Options   Options  `json:"options,omitempty"` // Additional options.
-	KeepAlive string   `json:"keep_alive,omitempty"`
-	Stream    bool     `json:"stream,omitempty"`
+	Model     string     `json:"model"`             // Model name.
+	Input     []string   `json:"input"`             // Input text(s).
+	Truncate  bool       `json:"truncate"`          // Whether to truncate input if needed.
+	Options   Options    `json:"options,omitempty"` // Additional options.
+	KeepAlive *KeepAlive `json:"keep_alive,omitempty"`
+	Stream    bool       `json:"stream,omitempty"`
+}
+
+// Validate checks the request's options before it is sent.
+func (r EmbeddingRequest) Validate() error {
+	return r.Options.Validate()
 }
 
 // =========================
@@ -43,10 +54,12 @@ type EmbeddingRequest struct {
 
 // ModelDetails contains specific metadata about a model.
 type ModelDetails struct {
-	Format        string `json:"format"`
-	Family        string `json:"family"`
-	ParameterSize string `json:"parameter_size"`
-	Quantization  string `json:"quantization_level"`
+	ParentModel   string   `json:"parent_model,omitempty"`
+	Format        string   `json:"format"`
+	Family        string   `json:"family"`
+	Families      []string `json:"families,omitempty"`
+	ParameterSize string   `json:"parameter_size"`
+	Quantization  string   `json:"quantization_level"`
 }
 
 // =========================
@@ -55,23 +68,65 @@ type ModelDetails struct {
 
 // ✅ **ShowModelRequest**: Used for retrieving model info.
 type ShowModelRequest struct {
-	Model string `json:"model"`
+	Model   string `json:"model"`
+	Verbose bool   `json:"verbose,omitempty"` // Optional: Include full tokenizer metadata and tensor information.
 }
 
 // ✅ **ModelManagementRequest**: Used for creating a model.
 type ModelManagementRequest struct {
-	Name  string `json:"name"`
-	Owner string `json:"owner,omitempty"`
+	Name       string                 `json:"name"`
+	Owner      string                 `json:"owner,omitempty"`
+	From       string                 `json:"from,omitempty"`       // Optional: Existing model to build on.
+	Files      map[string]string      `json:"files,omitempty"`      // Optional: Uploaded model files, file name to blob digest.
+	Adapters   map[string]string      `json:"adapters,omitempty"`   // Optional: Uploaded LoRA adapters, file name to blob digest.
+	Template   string                 `json:"template,omitempty"`   // Optional: Prompt template.
+	License    []string               `json:"license,omitempty"`    // Optional: License texts.
+	System     string                 `json:"system,omitempty"`     // Optional: System prompt.
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // Optional: Default model parameters (see Options).
+	Messages   []Message              `json:"messages,omitempty"`   // Optional: Example conversation.
+	Quantize   string                 `json:"quantize,omitempty"`   // Optional: Quantization type (e.g., "q4_K_M").
+	Stream     *bool                  `json:"stream,omitempty"`     // Optional: false returns a single final status instead of progress updates.
+}
+
+// PullRequest is used to download a model from a registry.
+type PullRequest struct {
+	Model    string `json:"model"`              // Required: Name of the model to pull.
+	Insecure bool   `json:"insecure,omitempty"` // Optional: Allow insecure connections to the registry.
+	Stream   *bool  `json:"stream,omitempty"`   // Optional: false returns a single final status instead of progress updates.
+}
+
+// PushRequest is used to upload a model to a registry.
+type PushRequest struct {
+	Model    string `json:"model"`              // Required: Name of the model to push (namespace/model:tag).
+	Insecure bool   `json:"insecure,omitempty"` // Optional: Allow insecure connections to the registry.
+	Stream   *bool  `json:"stream,omitempty"`   // Optional: false returns a single final status instead of progress updates.
+}
+
+// CopyRequest is used to copy a model under a new name.
+type CopyRequest struct {
+	Source      string `json:"source"`      // Required: Name of the existing model.
+	Destination string `json:"destination"` // Required: New name for the copy.
+}
+
+// DeleteRequest is used to delete a model.
+type DeleteRequest struct {
+	Model string `json:"model"` // Required: Name of the model to delete.
 }
 
 // CompletionRequest represents a request to generate text completion.
 type CompletionRequest struct {
-	Model     string   `json:"model"`                // Required: The model name to use.
-	Prompt    string   `json:"prompt,omitempty"`     // The prompt to generate a response for.
-	Suffix    string   `json:"suffix,omitempty"`     // The text to append after the model's response.
-	Images    []string `json:"images,omitempty"`     // List of base64-encoded images for multimodal models.
-	Options   Options  `json:"options,omitempty"`    // Advanced model parameters.
-	Stream    bool     `json:"stream,omitempty"`     // If true, returns a stream of responses.
-	Raw       bool     `json:"raw,omitempty"`        // If true, returns raw model output.
-	KeepAlive string   `json:"keep_alive,omitempty"` // Duration to keep the model loaded in memory.
+	Model     string      `json:"model"`                // Required: The model name to use.
+	Prompt    string      `json:"prompt,omitempty"`     // The prompt to generate a response for.
+	Suffix    string      `json:"suffix,omitempty"`     // The text to append after the model's response.
+	Images    []string    `json:"images,omitempty"`     // List of base64-encoded images for multimodal models.
+	Format    interface{} `json:"format,omitempty"`     // "json" or a JSON schema (e.g., *jsonschema.Schema).
+	Options   Options     `json:"options,omitempty"`    // Advanced model parameters.
+	Stream    bool        `json:"stream"`               // If true, returns a stream of responses (always sent: the server streams by default).
+	Raw       bool        `json:"raw,omitempty"`        // If true, returns raw model output.
+	KeepAlive *KeepAlive  `json:"keep_alive,omitempty"` // How long to keep the model loaded in memory.
+}
+
+// Validate checks the request's options before it is sent.
+func (r CompletionRequest) Validate() error {
+	return r.Options.Validate()
 }
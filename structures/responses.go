@@ -25,13 +25,19 @@ type CompletionResponse struct {
 
 // ChatResponse represents the model's reply in a chat conversation.
 type ChatResponse struct {
-	Model      string                 `json:"model"`                 // Model used for the response.
-	CreatedAt  time.Time              `json:"created_at"`            // Timestamp of response creation.
-	Message    Message                `json:"message"`               // Assistant's message.
-	DoneReason string                 `json:"done_reason,omitempty"` // Optional: Reason for stopping.
-	Done       bool                   `json:"done"`                  // Whether the chat response is complete.
-	ToolCalls  []ToolCall             `json:"tool_calls,omitempty"`  // Tool calls (structured return).
-	Metadata   map[string]interface{} `json:"metadata,omitempty"`    // Optional: Additional metadata.
+	Model              string                 `json:"model"`                          // Model used for the response.
+	CreatedAt          time.Time              `json:"created_at"`                     // Timestamp of response creation.
+	Message            Message                `json:"message"`                        // Assistant's message.
+	DoneReason         string                 `json:"done_reason,omitempty"`          // Optional: Reason for stopping.
+	Done               bool                   `json:"done"`                           // Whether the chat response is complete.
+	ToolCalls          []ToolCall             `json:"tool_calls,omitempty"`           // Tool calls (structured return).
+	TotalDuration      int64                  `json:"total_duration,omitempty"`       // Total time spent on the response (ns).
+	LoadDuration       int64                  `json:"load_duration,omitempty"`        // Time spent loading the model (ns).
+	PromptEvalCount    int                    `json:"prompt_eval_count,omitempty"`    // Number of tokens in the prompt.
+	PromptEvalDuration int64                  `json:"prompt_eval_duration,omitempty"` // Time spent evaluating the prompt (ns).
+	EvalCount          int                    `json:"eval_count,omitempty"`           // Number of tokens generated.
+	EvalDuration       int64                  `json:"eval_duration,omitempty"`        // Time spent generating tokens (ns).
+	Metadata           map[string]interface{} `json:"metadata,omitempty"`             // Optional: Additional metadata.
 }
 
 // =========================
@@ -62,6 +68,14 @@ type ModelInfo struct {
 	Details    ModelDetails `json:"details"`
 }
 
+// ProgressResponse is a status update streamed while pulling, pushing or creating a model.
+type ProgressResponse struct {
+	Status    string `json:"status"`              // Current step (e.g., "pulling manifest", "success").
+	Digest    string `json:"digest,omitempty"`    // Layer being transferred, if any.
+	Total     int64  `json:"total,omitempty"`     // Size of the layer in bytes.
+	Completed int64  `json:"completed,omitempty"` // Bytes of the layer transferred so far.
+}
+
 // =========================
 // == Model Process API ==
 // =========================
@@ -92,8 +106,22 @@ type VersionResponse struct {
 
 // ✅ **ShowModelResponse**: Contains model details.
 type ShowModelResponse struct {
-	Name        string   `json:"name"`
-	Version     string   `json:"version"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
+	Modelfile     string                 `json:"modelfile,omitempty"`      // Modelfile the model was created from.
+	Parameters    string                 `json:"parameters,omitempty"`     // Default parameters, one "key value" per line.
+	Template      string                 `json:"template,omitempty"`       // Prompt template.
+	System        string                 `json:"system,omitempty"`         // System prompt.
+	License       string                 `json:"license,omitempty"`        // License text.
+	Details       ModelDetails           `json:"details"`                  // Format, family, size and quantization.
+	ModelInfo     ModelMetadata          `json:"model_info,omitempty"`     // GGUF metadata (see the ModelMetadata accessors).
+	ProjectorInfo map[string]interface{} `json:"projector_info,omitempty"` // Metadata of the vision projector, if any.
+	Tensors       []Tensor               `json:"tensors,omitempty"`        // Tensor layout (verbose only).
+	Capabilities  []string               `json:"capabilities,omitempty"`   // Features the model supports (e.g., "tools").
+	ModifiedAt    time.Time              `json:"modified_at,omitempty"`    // Last modification time.
+}
+
+// Tensor describes a single weight tensor of a model.
+type Tensor struct {
+	Name  string   `json:"name"`
+	Type  string   `json:"type"`
+	Shape []uint64 `json:"shape"`
 }
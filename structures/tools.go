@@ -1,5 +1,7 @@
 package structures
 
+import "github.com/SamyRai/ollama-go/jsonschema"
+
 // =========================
 // == Tool API ==
 // =========================
@@ -12,16 +14,9 @@ type Tool struct {
 
 // ToolFunction describes the function a model can invoke.
 type ToolFunction struct {
-	Name        string               `json:"name"`        // Function name.
-	Description string               `json:"description"` // Function description.
-	Parameters  map[string]ToolParam `json:"parameters"`  // Function parameters.
-}
-
-// ToolParam defines a function parameter.
-type ToolParam struct {
-	Type        string   `json:"type"`           // Parameter data type.
-	Description string   `json:"description"`    // Parameter description.
-	Enum        []string `json:"enum,omitempty"` // Optional: Allowed values.
+	Name        string             `json:"name"`        // Function name.
+	Description string             `json:"description"` // Function description.
+	Parameters  *jsonschema.Schema `json:"parameters"`  // Function parameters as a JSON schema of type "object".
 }
 
 // ToolCall represents an invocation of a tool.
@@ -0,0 +1,165 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// headerEchoServer records the headers of the last request it received.
+func headerEchoServer(t *testing.T, status func(r *http.Request) int) (*httptest.Server, *http.Header) {
+	var last http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		last = r.Header.Clone()
+		if code := status(r); code != http.StatusOK {
+			w.WriteHeader(code)
+			fmt.Fprint(w, `{"error":"unauthorized"}`)
+			return
+		}
+		fmt.Fprint(w, `{"version":"0.5.11"}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+func alwaysOK(*http.Request) int { return http.StatusOK }
+
+// TestAPIKeyFromConfig ensures Config.APIKey is sent as a bearer token.
+func TestAPIKeyFromConfig(t *testing.T) {
+	server, headers := headerEchoServer(t, alwaysOK)
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, APIKey: "secret-key"})
+	_, err := cli.GetVersion()
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer secret-key", headers.Get("Authorization"))
+}
+
+// TestAuthenticators validates the built-in authenticators.
+func TestAuthenticators(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   client.Authenticator
+		header string
+		value  string
+	}{
+		{"Bearer token", client.BearerToken("abc"), "Authorization", "Bearer abc"},
+		{"Basic auth", client.BasicAuth{Username: "user", Password: "pass"}, "Authorization", "Basic dXNlcjpwYXNz"},
+		{"Custom headers", client.HeaderAuth{"X-Api-Key": "key-1"}, "X-Api-Key", "key-1"},
+		{"Chained", client.MultiAuth{client.BearerToken("abc"), client.HeaderAuth{"X-Tenant": "t1"}}, "X-Tenant", "t1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, headers := headerEchoServer(t, alwaysOK)
+
+			cli := client.NewClient(&config.Config{BaseURL: server.URL})
+			cli.Auth = tt.auth
+			_, err := cli.GetVersion()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, headers.Get(tt.header))
+		})
+	}
+}
+
+// TestTokenProviderRotation ensures rotating tokens are cached, refreshed, and dropped after a 401.
+func TestTokenProviderRotation(t *testing.T) {
+	server, headers := headerEchoServer(t, func(r *http.Request) int {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			return http.StatusUnauthorized
+		}
+		return http.StatusOK
+	})
+
+	fetches := 0
+	provider := client.NewTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("token-%d", fetches), time.Now().Add(time.Hour), nil
+	}, time.Minute)
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	cli.Auth = provider
+
+	_, err := cli.GetVersion()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrAuthentication))
+
+	_, err = cli.GetVersion()
+	require.NoError(t, err)
+	_, err = cli.GetVersion()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer token-2", headers.Get("Authorization"))
+	assert.Equal(t, 2, fetches)
+}
+
+// TestMultiAuthInvalidate ensures a token provider chained with other authenticators is refreshed after a 401.
+func TestMultiAuthInvalidate(t *testing.T) {
+	server, headers := headerEchoServer(t, func(r *http.Request) int {
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			return http.StatusUnauthorized
+		}
+		return http.StatusOK
+	})
+
+	fetches := 0
+	provider := client.NewTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		fetches++
+		return fmt.Sprintf("token-%d", fetches), time.Now().Add(time.Hour), nil
+	}, time.Minute)
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	cli.Auth = client.MultiAuth{provider, client.HeaderAuth{"X-Tenant": "t1"}}
+
+	_, err := cli.GetVersion()
+	require.ErrorIs(t, err, utils.ErrAuthentication)
+	_, err = cli.GetVersion()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer token-2", headers.Get("Authorization"))
+	assert.Equal(t, "t1", headers.Get("X-Tenant"))
+	assert.Equal(t, 2, fetches)
+}
+
+// TestTokenProviderFailure ensures provider errors are reported without contacting the server.
+func TestTokenProviderFailure(t *testing.T) {
+	server, headers := headerEchoServer(t, alwaysOK)
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	cli.Auth = client.NewTokenProvider(func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errors.New("identity provider unavailable")
+	}, 0)
+
+	_, err := cli.GetVersion()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrAuthentication))
+	assert.Nil(t, *headers)
+}
+
+// TestSecretsRedacted ensures credentials never appear in formatted output.
+func TestSecretsRedacted(t *testing.T) {
+	cfg := &config.Config{BaseURL: "http://localhost:11434", APIKey: "secret-key"}
+	cli := client.NewClient(cfg)
+	cli.Auth = client.MultiAuth{
+		client.BearerToken("secret-key"),
+		client.BasicAuth{Username: "user", Password: "secret-pass"},
+		client.HeaderAuth{"X-Api-Key": "secret-header"},
+	}
+
+	for _, out := range []string{
+		fmt.Sprintf("%v", cfg), fmt.Sprintf("%+v", *cfg), fmt.Sprintf("%#v", cfg),
+		fmt.Sprintf("%v", cli.Auth), fmt.Sprintf("%+v", cli), fmt.Sprintf("%#v", cli.Auth),
+	} {
+		assert.NotContains(t, out, "secret")
+	}
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// blobServer is an in-memory /api/blobs endpoint that verifies uploaded digests.
+type blobServer struct {
+	mu       sync.Mutex
+	blobs    map[string][]byte
+	uploads  int
+	failNext int // Number of uploads to reject with 503 before accepting.
+}
+
+func newBlobServer(t *testing.T) (*blobServer, *httptest.Server) {
+	b := &blobServer{blobs: map[string][]byte{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		digest := strings.TrimPrefix(r.URL.Path, "/api/blobs/")
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		switch r.Method {
+		case http.MethodHead:
+			if _, ok := b.blobs[digest]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case http.MethodPost:
+			b.uploads++
+			data, _ := io.ReadAll(r.Body)
+			if b.failNext > 0 {
+				b.failNext--
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			sum := sha256.Sum256(data)
+			if "sha256:"+hex.EncodeToString(sum[:]) != digest {
+				http.Error(w, `{"error":"digest mismatch"}`, http.StatusBadRequest)
+				return
+			}
+			b.blobs[digest] = data
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return b, server
+}
+
+func writeTempFile(t *testing.T, data []byte) string {
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// TestUploadBlob validates that a file is uploaded under its sha256 digest with progress, through the interceptors.
+func TestUploadBlob(t *testing.T) {
+	blobs, server := newBlobServer(t)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	var calls []string
+	cli.Use(func(ctx context.Context, call *client.Call, next client.Handler) (interface{}, error) {
+		calls = append(calls, call.Method+" "+call.Endpoint)
+		return next(ctx, call)
+	})
+
+	data := bytes.Repeat([]byte("gguf"), 100_000)
+	path := writeTempFile(t, data)
+
+	var events []client.ProgressEvent
+	digest, err := cli.UploadBlob(context.Background(), path, func(e client.ProgressEvent) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(data)
+	require.Equal(t, "sha256:"+hex.EncodeToString(sum[:]), digest)
+	require.Equal(t, data, blobs.blobs[digest])
+	require.Greater(t, len(events), 1)
+	require.Equal(t, "uploading", events[0].Status)
+	require.True(t, events[len(events)-1].Done)
+	require.Equal(t, int64(len(data)), events[len(events)-1].Completed)
+	require.Equal(t, []string{"HEAD /api/blobs/" + digest, "POST /api/blobs/" + digest}, calls)
+
+	fileDigest, err := client.FileDigest(path)
+	require.NoError(t, err)
+	require.Equal(t, digest, fileDigest)
+}
+
+// TestUploadBlobSkipsExisting validates that a blob the server already has is not uploaded again.
+func TestUploadBlobSkipsExisting(t *testing.T) {
+	blobs, server := newBlobServer(t)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	path := writeTempFile(t, []byte("adapter weights"))
+
+	first, err := cli.UploadBlob(context.Background(), path, nil)
+	require.NoError(t, err)
+	second, err := cli.UploadBlob(context.Background(), path, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.Equal(t, 1, blobs.uploads)
+
+	exists, err := cli.BlobExists(context.Background(), first)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+// TestUploadBlobRetries validates that a failed upload is retried from the start of the file.
+func TestUploadBlobRetries(t *testing.T) {
+	blobs, server := newBlobServer(t)
+	blobs.failNext = 2
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	data := []byte("retried upload")
+
+	digest, err := cli.UploadBlob(context.Background(), writeTempFile(t, data), nil)
+	require.NoError(t, err)
+	require.Equal(t, 3, blobs.uploads)
+	require.Equal(t, data, blobs.blobs[digest])
+}
+
+// TestCreateBlobDigestMismatch validates that the server's rejection of a wrong digest is returned.
+func TestCreateBlobDigestMismatch(t *testing.T) {
+	_, server := newBlobServer(t)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	digest := "sha256:" + strings.Repeat("0", 64)
+	err := cli.CreateBlob(context.Background(), digest, strings.NewReader("content"), 7)
+	require.ErrorContains(t, err, "digest mismatch")
+
+	err = cli.CreateBlob(context.Background(), "md5:abc", strings.NewReader("content"), 7)
+	require.ErrorContains(t, err, "invalid blob digest")
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/cmd/ollama-go/command"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCLI runs an ollama-go command against srv and returns its exit code, stdout and stderr.
+func runCLI(t *testing.T, srv *ollamatest.Server, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	args = append([]string{args[0], "--host", srv.URL}, args[1:]...)
+	code := command.Run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestCLIModels validates the model management commands in text and JSON output.
+func TestCLIModels(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2", Family: "llama", ParameterSize: "3.2B", Quantization: "Q4_K_M",
+		Capabilities: []string{"completion", "tools"}, ContextLength: 4096})
+
+	code, out, _ := runCLI(t, srv, "", "list")
+	require.Equal(t, command.ExitOK, code)
+	require.Contains(t, out, "NAME")
+	require.Contains(t, out, "llama3.2:latest")
+
+	code, out, _ = runCLI(t, srv, "", "list", "--json")
+	require.Equal(t, command.ExitOK, code)
+	var list structures.ModelListResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &list))
+	require.Len(t, list.Models, 1)
+
+	code, out, _ = runCLI(t, srv, "", "show", "llama3.2")
+	require.Equal(t, command.ExitOK, code)
+	require.Contains(t, out, "context length")
+	require.Contains(t, out, "4096")
+	require.Contains(t, out, "tools")
+
+	code, out, _ = runCLI(t, srv, "", "pull", "mistral", "--json")
+	require.Equal(t, command.ExitOK, code)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Contains(t, lines[len(lines)-1], `"status":"success"`)
+	code, _, errOut := runCLI(t, srv, "", "push", "mistral")
+	require.Equal(t, command.ExitOK, code)
+	require.Contains(t, errOut, "pushing manifest")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "adapter.gguf"), []byte("weights"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Modelfile"), []byte("FROM llama3.2\nADAPTER ./adapter.gguf\nSYSTEM Be terse.\n"), 0o644))
+	code, _, errOut = runCLI(t, srv, "", "create", "terse", "-f", filepath.Join(dir, "Modelfile"))
+	require.Equal(t, command.ExitOK, code, errOut)
+	code, out, _ = runCLI(t, srv, "", "show", "terse", "--system")
+	require.Equal(t, command.ExitOK, code)
+	require.Equal(t, "Be terse.\n", out)
+
+	code, out, _ = runCLI(t, srv, "", "cp", "terse", "terse2")
+	require.Equal(t, command.ExitOK, code)
+	require.Equal(t, "copied 'terse' to 'terse2'\n", out)
+	code, out, _ = runCLI(t, srv, "", "rm", "terse", "terse2", "--json")
+	require.Equal(t, command.ExitOK, code)
+	require.JSONEq(t, `{"deleted":["terse","terse2"]}`, out)
+
+	runCLI(t, srv, "", "generate", "llama3.2", "hi")
+	code, out, _ = runCLI(t, srv, "", "ps")
+	require.Equal(t, command.ExitOK, code)
+	require.Contains(t, out, "llama3.2:latest")
+
+	srv.Version = "0.9.0"
+	code, out, _ = runCLI(t, srv, "", "version", "--json")
+	require.Equal(t, command.ExitOK, code)
+	require.Contains(t, out, `"server": "0.9.0"`)
+}
+
+// TestCLIGenerate validates generate, chat and embed, including the chat REPL and saved sessions.
+func TestCLIGenerate(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+
+	code, out, _ := runCLI(t, srv, "", "generate", "llama3.2", "--temperature", "0", "Say", "hello")
+	require.Equal(t, command.ExitOK, code)
+	require.Equal(t, "Say hello\n", out)
+	var sent structures.CompletionRequest
+	last, _ := srv.LastRequest("/api/generate")
+	require.NoError(t, last.Decode(&sent))
+	require.Equal(t, 0.0, *sent.Options.Temperature)
+	require.True(t, sent.Stream)
+
+	code, out, _ = runCLI(t, srv, "from stdin\n", "generate", "llama3.2", "--json")
+	require.Equal(t, command.ExitOK, code)
+	var resp structures.CompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(out), &resp))
+	require.Equal(t, "from stdin", resp.Response)
+
+	srv.Enqueue("/api/chat", ollamatest.Reply("Hi there!"))
+	code, out, _ = runCLI(t, srv, "", "chat", "llama3.2", "--system", "Be nice.", "Hello")
+	require.Equal(t, command.ExitOK, code)
+	require.Equal(t, "Hi there!\n", out)
+
+	dir := t.TempDir()
+	input := "first\n/system Be brief.\n\"\"\"two\nlines\"\"\"\n/history\n/bye\nnever sent\n"
+	code, out, errOut := runCLI(t, srv, input, "chat", "llama3.2", "--session", "demo", "--session-dir", dir, "--no-stream")
+	require.Equal(t, command.ExitOK, code, errOut)
+	require.Equal(t, "first\ntwo\nlines\nuser: first\nassistant: first\nuser: two\nlines\nassistant: two\nlines\n", out)
+	var chat structures.ChatRequest
+	last, _ = srv.LastRequest("/api/chat")
+	require.NoError(t, last.Decode(&chat))
+	require.Equal(t, structures.Message{Role: "system", Content: "Be brief."}, chat.Messages[0])
+
+	code, _, _ = runCLI(t, srv, "third\n", "chat", "llama3.2", "--session", "demo", "--session-dir", dir)
+	require.Equal(t, command.ExitOK, code)
+	last, _ = srv.LastRequest("/api/chat")
+	require.NoError(t, last.Decode(&chat))
+	require.Len(t, chat.Messages, 6)
+	require.Equal(t, "third", chat.Messages[5].Content)
+
+	code, out, _ = runCLI(t, srv, "one\ntwo\n", "embed", "llama3.2")
+	require.Equal(t, command.ExitOK, code)
+	vectors := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, vectors, 2)
+	var vector []float32
+	require.NoError(t, json.Unmarshal([]byte(vectors[0]), &vector))
+	require.NotEmpty(t, vector)
+}
+
+// TestCLIErrors validates exit codes for invalid command lines and failed requests.
+func TestCLIErrors(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, command.ExitUsage, command.Run(context.Background(), []string{"frobnicate"}, nil, &stdout, &stderr))
+	require.Contains(t, stderr.String(), `unknown command "frobnicate"`)
+	require.Equal(t, command.ExitOK, command.Run(context.Background(), nil, nil, &stdout, &stderr))
+	require.Contains(t, stdout.String(), "pull")
+
+	code, _, errOut := runCLI(t, srv, "", "cp", "only-one")
+	require.Equal(t, command.ExitUsage, code)
+	require.Contains(t, errOut, "Usage: ollama-go cp")
+	code, _, _ = runCLI(t, srv, "", "generate", "llama3.2", "--temperature", "hot")
+	require.Equal(t, command.ExitUsage, code)
+
+	code, _, errOut = runCLI(t, srv, "", "show", "missing")
+	require.Equal(t, command.ExitError, code)
+	require.Contains(t, errOut, "ollama-go show:")
+	code, _, _ = runCLI(t, srv, "hello\n", "chat", "missing")
+	require.Equal(t, command.ExitError, code)
+}
@@ -26,7 +26,7 @@ func TestGenerateCompletion(t *testing.T) {
 		Stream: false,
 	}
 
-	resp, err := cli.GenerateCompletion(req)
+	resp, err := cli.GenerateCompletion(req, nil)
 
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -49,7 +49,7 @@ func TestGenerateCompletionStream(t *testing.T) {
 		Stream: true,
 	}
 
-	resp, err := cli.GenerateCompletion(req)
+	resp, err := cli.GenerateCompletion(req, nil)
 	log.Printf("resp.Response: %v", resp)
 	require.NoError(t, err)
 	assert.NotEmpty(t, resp)
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestChatContextCancelStopsStream ensures cancelling the context stops reading NDJSON chunks.
+func TestChatContextCancelStopsStream(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		fmt.Fprintln(w, `{"model":"llama3.1","message":{"role":"assistant","content":"Hello"},"done":false}`)
+		w.(http.Flusher).Flush()
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Timeout: time.Minute})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var chunks []structures.ChatResponse
+	start := time.Now()
+	_, err := cli.ChatContext(ctx, structures.ChatRequest{Model: "llama3.1", Stream: true}, func(resp structures.ChatResponse) {
+		chunks = append(chunks, resp)
+		cancel()
+	})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Len(t, chunks, 1)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+// TestRequestContextDeadline ensures a per-call deadline overrides the client's default timeout.
+func TestRequestContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, `{"version":"0.5.11"}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Timeout: 50 * time.Millisecond})
+
+	_, err := cli.GetVersion()
+	require.Error(t, err, "default timeout should apply without a deadline")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := cli.GetVersionContext(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0.5.11", resp.Version)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = cli.GetVersionContext(ctx)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
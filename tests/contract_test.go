@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"github.com/SamyRai/ollama-go/contract"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+// TestContractSpec validates that swagger.yml documents every client endpoint and structure field exactly.
+func TestContractSpec(t *testing.T) {
+	spec, err := contract.Load("../swagger.yml")
+	require.NoError(t, err)
+
+	for _, p := range contract.Check(spec, contract.ClientEndpoints()) {
+		t.Error(p)
+	}
+}
+
+// TestContractDrift validates that each kind of drift between the spec and the Go types is reported.
+func TestContractDrift(t *testing.T) {
+	spec, err := contract.Parse([]byte(`
+paths:
+  /api/copy:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                source: {type: string}
+                destination: {type: integer}
+                overwrite: {type: boolean}
+  /api/version:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Missing'}
+  /api/blobs/{digest}:
+    delete: {}
+`))
+	require.NoError(t, err)
+
+	endpoints := []contract.Endpoint{
+		{Method: "POST", Path: "/api/copy", Request: reflect.TypeOf(structures.CopyRequest{})},
+		{Method: "GET", Path: "/api/version", Response: reflect.TypeOf(structures.VersionResponse{})},
+		{Method: "GET", Path: "/api/tags", Response: reflect.TypeOf(structures.ModelListResponse{})},
+	}
+	kinds := map[string]contract.Kind{}
+	for _, p := range contract.Check(spec, endpoints) {
+		kinds[p.Endpoint+" "+p.Field] = p.Kind
+	}
+	require.Equal(t, map[string]contract.Kind{
+		"POST /api/copy request.destination": contract.TypeMismatch,
+		"POST /api/copy request.overwrite":   contract.MissingField,
+		"GET /api/version response":          contract.InvalidSpec,
+		"GET /api/tags ":                     contract.MissingEndpoint,
+		"DELETE /api/blobs/{digest} ":        contract.UnusedEndpoint,
+	}, kinds)
+
+	problems := contract.CheckPayload(spec, "POST", "/api/copy", []byte(`{"source":"a","destination":"b","force":true}`))
+	require.Len(t, problems, 2)
+	require.Equal(t, contract.TypeMismatch, problems[0].Kind)
+	require.Equal(t, "request.destination", problems[0].Field)
+	require.Equal(t, contract.Undocumented, problems[1].Kind)
+	require.Equal(t, "request.force", problems[1].Field)
+}
+
+// TestContractPayloads validates the JSON the client actually sends, for every endpoint, against swagger.yml.
+func TestContractPayloads(t *testing.T) {
+	spec, err := contract.Load("../swagger.yml")
+	require.NoError(t, err)
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+	cli := srv.Client()
+	ctx := context.Background()
+
+	opts, err := structures.NewOptions().Temperature(0).Seed(42).Stop("END").Build()
+	require.NoError(t, err)
+	msgs := []structures.Message{{Role: "user", Content: "hi", Images: []string{"aGk="}}}
+	_, err = cli.ChatContext(ctx, structures.ChatRequest{
+		Model: "llama3.2", Messages: msgs, Options: opts, Format: "json", KeepAlive: structures.Ptr(structures.KeepAliveForever),
+		Tools: []structures.Tool{{Type: "function", Function: structures.ToolFunction{Name: "noop", Description: "Does nothing"}}},
+	}, nil)
+	require.NoError(t, err)
+	_, err = cli.GenerateCompletionContext(ctx, structures.CompletionRequest{Model: "llama3.2", Prompt: "hi", Suffix: "!", Options: opts}, nil)
+	require.NoError(t, err)
+	_, err = cli.GenerateEmbeddingsContext(ctx, structures.EmbeddingRequest{Model: "llama3.2", Input: []string{"hi"}, Truncate: true})
+	require.NoError(t, err)
+	require.NoError(t, cli.LoadModel(ctx, "llama3.2", nil))
+	require.NoError(t, cli.UnloadModel(ctx, "llama3.2"))
+	_, err = cli.ShowModelContext(ctx, structures.ShowModelRequest{Model: "llama3.2", Verbose: true})
+	require.NoError(t, err)
+	require.NoError(t, cli.CreateModelContext(ctx, structures.ModelManagementRequest{Name: "copy", From: "llama3.2", System: "Be brief.", Messages: msgs}))
+	require.NoError(t, cli.CopyModelContext(ctx, "copy", "copy2"))
+	require.NoError(t, cli.DeleteModelContext(ctx, "copy2"))
+	require.NoError(t, cli.PullModelContext(ctx, "llama3.2"))
+	require.NoError(t, cli.PushModelContext(ctx, "llama3.2"))
+	_, err = cli.UploadBlob(ctx, writeTempFile(t, []byte("weights")), nil)
+	require.NoError(t, err)
+	_, err = cli.ListModelsContext(ctx)
+	require.NoError(t, err)
+	_, err = cli.GetRunningProcessesContext(ctx)
+	require.NoError(t, err)
+	_, err = cli.GetVersionContext(ctx)
+	require.NoError(t, err)
+
+	called := map[string]bool{}
+	for _, r := range srv.Requests() {
+		for _, p := range contract.CheckPayload(spec, r.Method, r.Path, r.Body) {
+			t.Error(p)
+		}
+		op, template := spec.Operation(r.Method, r.Path)
+		require.NotNil(t, op, "%s %s", r.Method, r.Path)
+		called[r.Method+" "+template] = true
+	}
+	for _, e := range contract.ClientEndpoints() {
+		require.True(t, called[e.String()], "%s was not exercised", e)
+	}
+}
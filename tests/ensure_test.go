@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const (
+	pulledDigest = "a80c4f17acd55265feec403c7aef86be0c25983ab279d83f3bcd3abbcb5b8b72"
+	otherDigest  = "365c0bd3c000a25d28ddbf732fe1c6add414de7275464c4e4d1c3b5fcb5d8ad1"
+)
+
+// registryServer serves /api/tags and /api/pull, listing the model with pulledDigest once it has been pulled.
+func registryServer(t *testing.T, initialDigest string) (*httptest.Server, *int32) {
+	var pulls int32
+	var mu sync.Mutex
+	digest := initialDigest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/tags":
+			mu.Lock()
+			defer mu.Unlock()
+			if digest == "" {
+				fmt.Fprint(w, `{"models":[]}`)
+				return
+			}
+			fmt.Fprintf(w, `{"models":[{"name":"llama3.2:latest","digest":%q,"size":2019393189}]}`, digest)
+		case "/api/pull":
+			atomic.AddInt32(&pulls, 1)
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprintln(w, `{"status":"pulling manifest"}`)
+			fmt.Fprintln(w, `{"status":"pulling a80c","digest":"sha256:a80c","total":10,"completed":10}`)
+			fmt.Fprintln(w, `{"status":"success"}`)
+			mu.Lock()
+			digest = pulledDigest
+			mu.Unlock()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &pulls
+}
+
+// TestEnsureModelPullsMissing validates pulling a missing model and reporting progress.
+func TestEnsureModelPullsMissing(t *testing.T) {
+	server, pulls := registryServer(t, "")
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	events := 0
+	info, err := cli.EnsureModel(context.Background(), "llama3.2", func(client.ProgressEvent) { events++ })
+	require.NoError(t, err)
+	require.Equal(t, "llama3.2:latest", info.Name)
+	require.Equal(t, pulledDigest, info.Digest)
+	require.Equal(t, int32(1), atomic.LoadInt32(pulls))
+	require.Equal(t, 3, events)
+
+	// Already present: no second pull
+	_, err = cli.EnsureModel(context.Background(), "llama3.2:latest@sha256:"+pulledDigest[:12], nil)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(pulls))
+}
+
+// TestEnsureModelDigestPin validates re-pulling on a digest mismatch and failing if the pin is still unmet.
+func TestEnsureModelDigestPin(t *testing.T) {
+	server, pulls := registryServer(t, otherDigest)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	info, err := cli.EnsureModel(context.Background(), "llama3.2@"+pulledDigest, nil)
+	require.NoError(t, err)
+	require.Equal(t, pulledDigest, info.Digest)
+	require.Equal(t, int32(1), atomic.LoadInt32(pulls))
+
+	_, err = cli.EnsureModel(context.Background(), "llama3.2@"+otherDigest[:16], nil)
+	require.ErrorIs(t, err, utils.ErrDigestMismatch)
+	require.Equal(t, int32(2), atomic.LoadInt32(pulls))
+
+	_, err = cli.EnsureModel(context.Background(), "llama3.2@abc", nil)
+	require.ErrorContains(t, err, "at least 12 hex digits")
+}
+
+// TestEnsureModelDedupes validates that concurrent ensures of one model share a single pull.
+func TestEnsureModelDedupes(t *testing.T) {
+	server, pulls := registryServer(t, "")
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var wg sync.WaitGroup
+	var events int32
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := cli.EnsureModel(context.Background(), "llama3.2", func(client.ProgressEvent) {
+				atomic.AddInt32(&events, 1)
+			})
+			require.NoError(t, err)
+			require.Equal(t, pulledDigest, info.Digest)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(pulls))
+	require.Positive(t, atomic.LoadInt32(&events))
+}
+
+// TestEnsureModelDedupesPins validates that ensures of one model with different pins share a single pull.
+func TestEnsureModelDedupesPins(t *testing.T) {
+	server, pulls := registryServer(t, "")
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var wg sync.WaitGroup
+	for _, ref := range []string{"llama3.2", "llama3.2:latest@" + pulledDigest[:12], "llama3.2@sha256:" + pulledDigest} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := cli.EnsureModel(context.Background(), ref, nil)
+			require.NoError(t, err)
+			require.Equal(t, pulledDigest, info.Digest)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), atomic.LoadInt32(pulls))
+}
+
+// TestEnsureModelCallerLeaves validates that a caller giving up does not fail the others, and that a pull
+// longer than the client's timeout completes.
+func TestEnsureModelCallerLeaves(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.ChunkDelay = 40 * time.Millisecond
+	cli := client.NewClient(&config.Config{BaseURL: srv.URL, Timeout: 100 * time.Millisecond})
+
+	impatient, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	var once sync.Once
+	first := make(chan error, 1)
+	go func() {
+		_, err := cli.EnsureModel(impatient, "llama3.2", func(client.ProgressEvent) { once.Do(func() { close(started) }) })
+		first <- err
+	}()
+	<-started
+
+	second := make(chan error, 1)
+	go func() {
+		_, err := cli.EnsureModel(context.Background(), "llama3.2", nil)
+		second <- err
+	}()
+	time.Sleep(20 * time.Millisecond) // Let the second caller join the pull
+	cancel()
+	require.ErrorIs(t, <-first, context.Canceled)
+	require.NoError(t, <-second)
+	require.Len(t, srv.RequestsTo("/api/pull"), 1)
+}
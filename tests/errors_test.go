@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAPIErrorMapping validates that failed responses become APIErrors matching the sentinels.
+func TestAPIErrorMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+		message  string
+	}{
+		{"Model not found", http.StatusNotFound, `{"error":"model 'llama9' not found"}`, utils.ErrModelNotFound, "model 'llama9' not found"},
+		{"Overloaded", http.StatusServiceUnavailable, `{"error":"server busy, please try again"}`, utils.ErrOverloaded, "server busy, please try again"},
+		{"Bad input", http.StatusBadRequest, `{"error":"invalid options"}`, utils.ErrBadRequest, "invalid options"},
+		{"Gateway timeout", http.StatusGatewayTimeout, `upstream timed out`, utils.ErrTimeout, "upstream timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "req-123")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			cli := client.NewClient(&config.Config{BaseURL: server.URL})
+			_, err := cli.Chat(structures.ChatRequest{Model: "llama9"}, nil)
+			require.Error(t, err)
+
+			var apiErr *utils.APIError
+			require.True(t, errors.As(err, &apiErr))
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+			assert.Equal(t, tt.message, apiErr.Message)
+			assert.Equal(t, "/api/chat", apiErr.Endpoint)
+			assert.Equal(t, "req-123", apiErr.RequestID)
+			assert.True(t, errors.Is(err, tt.sentinel))
+			assert.True(t, errors.Is(err, utils.ErrRequestFailed))
+		})
+	}
+}
+
+// TestStreamErrorChunk ensures an error sent mid-stream is returned instead of being dropped.
+func TestStreamErrorChunk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"model":"llama3.1","response":"Hi","done":false}`)
+		fmt.Fprintln(w, `{"error":"model \"llama3.1\" not found, try pulling it first"}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	var chunks int
+	_, err := cli.GenerateCompletion(structures.CompletionRequest{Model: "llama3.1", Stream: true}, func(structures.CompletionResponse) {
+		chunks++
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, chunks)
+	assert.True(t, errors.Is(err, utils.ErrModelNotFound))
+}
+
+// TestInvalidResponse ensures undecodable success bodies map to ErrInvalidResponse.
+func TestInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `not json`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	_, err := cli.GetVersion()
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrInvalidResponse))
+}
@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/gateway"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newGateway starts a gateway in front of a fake Ollama server with llama3.2 and an embedding model.
+func newGateway(t *testing.T) (*ollamatest.Server, *gateway.Gateway, *httptest.Server) {
+	t.Helper()
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2", Capabilities: []string{"completion", "tools"}},
+		ollamatest.Model{Name: "nomic-embed-text", EmbeddingLength: 4})
+	g := gateway.New(srv.Client())
+	front := httptest.NewServer(g)
+	t.Cleanup(front.Close)
+	return srv, g, front
+}
+
+// postGateway sends a JSON body and returns the response status and body.
+func postGateway(t *testing.T, front *httptest.Server, path, body string) (int, []byte) {
+	t.Helper()
+	resp, err := http.Post(front.URL+path, "application/json", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, data
+}
+
+// readEvents reads the data lines of a server-sent event stream up to [DONE].
+func readEvents(t *testing.T, body io.Reader) []string {
+	t.Helper()
+	var events []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			return events
+		}
+		events = append(events, data)
+	}
+	t.Fatal("stream ended without [DONE]")
+	return nil
+}
+
+// TestGatewayChat validates chat completions: plain, streamed with usage, tool calls and response formats.
+func TestGatewayChat(t *testing.T) {
+	srv, _, front := newGateway(t)
+
+	status, body := postGateway(t, front, "/v1/chat/completions",
+		`{"model":"llama3.2","messages":[{"role":"system","content":"Be nice."},{"role":"user","content":"Say hi"}],"max_tokens":20,"temperature":0.5}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	var completion gateway.ChatCompletion
+	require.NoError(t, json.Unmarshal(body, &completion))
+	require.True(t, strings.HasPrefix(completion.ID, "chatcmpl-"))
+	require.Equal(t, "chat.completion", completion.Object)
+	require.Equal(t, "assistant", completion.Choices[0].Message.Role)
+	require.Equal(t, "Say hi", completion.Choices[0].Message.Content.Text)
+	require.Equal(t, "stop", completion.Choices[0].FinishReason)
+	require.Equal(t, 2, completion.Usage.CompletionTokens)
+	var sent structures.ChatRequest
+	last, _ := srv.LastRequest("/api/chat")
+	require.NoError(t, last.Decode(&sent))
+	require.Equal(t, 20, *sent.Options.NumPredict)
+	require.Equal(t, 0.5, *sent.Options.Temperature)
+	require.Len(t, sent.Messages, 2)
+
+	srv.Enqueue("/api/chat", ollamatest.Chunks("Hel", "lo"))
+	resp, err := http.Post(front.URL+"/v1/chat/completions", "application/json", strings.NewReader(
+		`{"model":"llama3.2","messages":[{"role":"user","content":"hi"}],"stream":true,"stream_options":{"include_usage":true}}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	events := readEvents(t, resp.Body)
+	require.Len(t, events, 4)
+	var chunks []gateway.ChatCompletionChunk
+	for _, event := range events {
+		var chunk gateway.ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(event), &chunk))
+		chunks = append(chunks, chunk)
+	}
+	require.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	require.Equal(t, "Hel", chunks[0].Choices[0].Delta.Content)
+	require.Empty(t, chunks[1].Choices[0].Delta.Role)
+	require.Equal(t, "lo", chunks[1].Choices[0].Delta.Content)
+	require.Nil(t, chunks[1].Choices[0].FinishReason)
+	require.Equal(t, "stop", *chunks[2].Choices[0].FinishReason)
+	require.Empty(t, chunks[3].Choices)
+	require.Equal(t, 2, chunks[3].Usage.CompletionTokens)
+	require.Equal(t, chunks[0].ID, chunks[3].ID)
+
+	srv.Enqueue("/api/chat", ollamatest.ToolCall("get_weather", map[string]interface{}{"city": "Paris"}))
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"llama3.2","messages":[{"role":"user","content":"Weather?"}],
+		"tools":[{"type":"function","function":{"name":"get_weather","parameters":{"type":"object",
+		"properties":{"city":{"type":"string"}},"required":["city"],"additionalProperties":false}}}]}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	completion = gateway.ChatCompletion{}
+	require.NoError(t, json.Unmarshal(body, &completion))
+	require.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	call := completion.Choices[0].Message.ToolCalls[0]
+	require.True(t, strings.HasPrefix(call.ID, "call_"))
+	require.Equal(t, "get_weather", call.Function.Name)
+	require.JSONEq(t, `{"city":"Paris"}`, call.Function.Arguments)
+	last, _ = srv.LastRequest("/api/chat")
+	require.NoError(t, last.Decode(&sent))
+	require.Equal(t, "get_weather", sent.Tools[0].Function.Name)
+	require.Equal(t, []string{"city"}, sent.Tools[0].Function.Parameters.Required)
+
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"llama3.2","messages":[
+		{"role":"user","content":"Weather?"},
+		{"role":"assistant","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},
+		{"role":"tool","tool_call_id":"call_1","content":"Sunny"}],
+		"response_format":{"type":"json_schema","json_schema":{"name":"w","schema":{"type":"object"}}}}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	last, _ = srv.LastRequest("/api/chat")
+	var raw map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(last.Body, &raw))
+	require.JSONEq(t, `{"type":"object"}`, string(raw["format"]))
+	require.NoError(t, last.Decode(&sent))
+	require.Equal(t, "tool", sent.Messages[2].Role)
+	require.Equal(t, "Sunny", sent.Messages[2].Content)
+}
+
+// TestGatewayCompletionsAndEmbeddings validates legacy completions, embeddings and the models API.
+func TestGatewayCompletionsAndEmbeddings(t *testing.T) {
+	srv, _, front := newGateway(t)
+
+	status, body := postGateway(t, front, "/v1/completions", `{"model":"llama3.2","prompt":"one two","suffix":"end"}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	var completion gateway.Completion
+	require.NoError(t, json.Unmarshal(body, &completion))
+	require.Equal(t, "text_completion", completion.Object)
+	require.Equal(t, "one two", completion.Choices[0].Text)
+	require.Equal(t, "stop", *completion.Choices[0].FinishReason)
+	var sent structures.CompletionRequest
+	last, _ := srv.LastRequest("/api/generate")
+	require.NoError(t, last.Decode(&sent))
+	require.Equal(t, "end", sent.Suffix)
+
+	resp, err := http.Post(front.URL+"/v1/completions", "application/json", strings.NewReader(`{"model":"llama3.2","prompt":["a b"],"stream":true}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var text strings.Builder
+	for _, event := range readEvents(t, resp.Body) {
+		var chunk gateway.Completion
+		require.NoError(t, json.Unmarshal([]byte(event), &chunk))
+		text.WriteString(chunk.Choices[0].Text)
+	}
+	require.Equal(t, "a b", text.String())
+
+	status, body = postGateway(t, front, "/v1/embeddings", `{"model":"nomic-embed-text","input":["hello","world"]}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	var floats struct {
+		Data []struct {
+			Embedding []float32 `json:"embedding"`
+			Index     int       `json:"index"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &floats))
+	require.Len(t, floats.Data, 2)
+	require.Equal(t, 1, floats.Data[1].Index)
+	require.Equal(t, ollamatest.Embedding("hello", 4), floats.Data[0].Embedding)
+
+	status, body = postGateway(t, front, "/v1/embeddings", `{"model":"nomic-embed-text","input":"hello","encoding_format":"base64"}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	var encoded struct {
+		Data []struct {
+			Embedding string `json:"embedding"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &encoded))
+	data, err := base64.StdEncoding.DecodeString(encoded.Data[0].Embedding)
+	require.NoError(t, err)
+	require.Len(t, data, 16)
+	require.Equal(t, ollamatest.Embedding("hello", 4)[0], math.Float32frombits(binary.LittleEndian.Uint32(data)))
+
+	resp, err = http.Get(front.URL + "/v1/models")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var models gateway.ModelList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&models))
+	require.Len(t, models.Data, 2)
+	require.Equal(t, "library", models.Data[0].OwnedBy)
+
+	resp, err = http.Get(front.URL + "/v1/models/llama3.2")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// TestGatewayErrors validates authentication, aliases and the mapping of failures to OpenAI errors.
+func TestGatewayErrors(t *testing.T) {
+	srv, g, front := newGateway(t)
+	decodeError := func(body []byte) gateway.ErrorDetail {
+		var resp gateway.ErrorResponse
+		require.NoError(t, json.Unmarshal(body, &resp), string(body))
+		return resp.Error
+	}
+
+	status, body := postGateway(t, front, "/v1/chat/completions", `{"model":"missing","messages":[{"role":"user","content":"hi"}]}`)
+	require.Equal(t, http.StatusNotFound, status)
+	require.Equal(t, "model_not_found", *decodeError(body).Code)
+
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "invalid_request_error", decodeError(body).Type)
+
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"llama3.2","messages":[{"role":"user","content":"hi"}],"n":2}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "n", *decodeError(body).Param)
+
+	status, body = postGateway(t, front, "/v1/embeddings", `{"model":"nomic-embed-text","input":"hi","dimensions":2}`)
+	require.Equal(t, http.StatusBadRequest, status)
+	require.Equal(t, "dimensions", *decodeError(body).Param)
+
+	srv.Enqueue("/api/chat", ollamatest.Fail(http.StatusServiceUnavailable, "server busy"))
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"llama3.2","messages":[{"role":"user","content":"hi"}],"stream":true}`)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, "server busy", decodeError(body).Message)
+
+	status, _ = postGateway(t, front, "/v1/files", `{}`)
+	require.Equal(t, http.StatusNotFound, status)
+
+	g.Aliases = map[string]string{"gpt-4o": "llama3.2"}
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+	require.Equal(t, http.StatusOK, status, string(body))
+	var completion gateway.ChatCompletion
+	require.NoError(t, json.Unmarshal(body, &completion))
+	require.Equal(t, "gpt-4o", completion.Model)
+
+	g.APIKey = "secret"
+	status, body = postGateway(t, front, "/v1/chat/completions", `{"model":"gpt-4o","messages":[{"role":"user","content":"hi"}]}`)
+	require.Equal(t, http.StatusUnauthorized, status)
+	require.Equal(t, "invalid_api_key", *decodeError(body).Code)
+	req, err := http.NewRequest(http.MethodGet, front.URL+"/v1/models", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
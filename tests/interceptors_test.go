@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// chatEchoServer replies with the requested model name, streaming two chunks when asked to.
+func chatEchoServer(t *testing.T, hits *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+		var req structures.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Stream {
+			fmt.Fprintf(w, `{"model":%q,"message":{"role":"assistant","content":"Hel"},"done":false}`+"\n", req.Model)
+			fmt.Fprintf(w, `{"model":%q,"message":{"role":"assistant","content":"lo"},"done":true}`+"\n", req.Model)
+			return
+		}
+		fmt.Fprintf(w, `{"model":%q,"message":{"role":"assistant","content":"Hello"},"done":true}`, req.Model)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestInterceptorOrderAndRewrite validates ordering, request rewriting and response inspection.
+func TestInterceptorOrderAndRewrite(t *testing.T) {
+	var hits int
+	server := chatEchoServer(t, &hits)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var order []string
+	cli.Use(
+		func(ctx context.Context, call *client.Call, next client.Handler) (interface{}, error) {
+			order = append(order, "outer:before")
+			resp, err := next(ctx, call)
+			order = append(order, "outer:after")
+			return resp, err
+		},
+		func(ctx context.Context, call *client.Call, next client.Handler) (interface{}, error) {
+			order = append(order, "inner:before")
+			req := call.Request.(structures.ChatRequest)
+			req.Model = "rewritten"
+			call.Request = req
+
+			resp, err := next(ctx, call)
+			chatResp := resp.(*structures.ChatResponse)
+			chatResp.Message.Content = strings.ToUpper(chatResp.Message.Content)
+			order = append(order, "inner:after")
+			return chatResp, err
+		},
+	)
+
+	resp, err := cli.Chat(structures.ChatRequest{Model: "llama3.1"}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "rewritten", resp.Model)
+	assert.Equal(t, "HELLO", resp.Message.Content)
+	assert.Equal(t, []string{"outer:before", "inner:before", "inner:after", "outer:after"}, order)
+}
+
+// TestInterceptorShortCircuit ensures an interceptor can answer without contacting the server.
+func TestInterceptorShortCircuit(t *testing.T) {
+	var hits int
+	server := chatEchoServer(t, &hits)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	cli.Use(func(ctx context.Context, call *client.Call, next client.Handler) (interface{}, error) {
+		if call.Endpoint == "/api/version" {
+			return &structures.VersionResponse{Version: "cached"}, nil
+		}
+		return next(ctx, call)
+	})
+
+	resp, err := cli.GetVersion()
+
+	require.NoError(t, err)
+	assert.Equal(t, "cached", resp.Version)
+	assert.Equal(t, 0, hits)
+}
+
+// TestInterceptorStreamChunks ensures interceptors observe each decoded stream chunk.
+func TestInterceptorStreamChunks(t *testing.T) {
+	var hits int
+	server := chatEchoServer(t, &hits)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var seen []string
+	cli.Use(func(ctx context.Context, call *client.Call, next client.Handler) (interface{}, error) {
+		onChunk := call.OnChunk
+		call.OnChunk = func(chunk interface{}) error {
+			seen = append(seen, chunk.(*structures.ChatResponse).Message.Content)
+			return onChunk(chunk)
+		}
+		return next(ctx, call)
+	})
+
+	var received []string
+	resp, err := cli.Chat(structures.ChatRequest{Model: "llama3.1", Stream: true}, func(chunk structures.ChatResponse) {
+		received = append(received, chunk.Message.Content)
+	})
+
+	require.NoError(t, err)
+	assert.True(t, resp.Done)
+	assert.Equal(t, []string{"Hel", "lo"}, seen)
+	assert.Equal(t, []string{"Hel", "lo"}, received)
+}
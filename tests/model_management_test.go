@@ -1,9 +1,13 @@
 package tests
 
 import (
+	"errors"
 	"github.com/SamyRai/ollama-go/client"
 	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/ollamatest"
 	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/dnaeon/go-vcr.v2/recorder"
 	"testing"
@@ -11,21 +15,24 @@ import (
 
 // TestCreateModel validates model creation.
 func TestCreateModel(t *testing.T) {
-	rec, err := recorder.New("fixtures/create_model")
-	require.NoError(t, err)
-	defer rec.Stop()
-
-	cli := client.NewClient(config.DefaultConfig())
-	cli.HTTPClient.Transport = rec
-
-	req := structures.ModelManagementRequest{Name: "test-model"}
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
 
-	err = cli.CreateModel(req)
+	err := srv.Client().CreateModel(structures.ModelManagementRequest{Name: "test-model", From: "llama3.2"})
 
 	require.NoError(t, err)
+	var received structures.ModelManagementRequest
+	req, ok := srv.LastRequest("/api/create")
+	require.True(t, ok)
+	require.NoError(t, req.Decode(&received))
+	require.Equal(t, "test-model", received.Name)
+	require.Equal(t, "llama3.2", received.From)
+	models := srv.Models()
+	require.Len(t, models, 2)
+	require.Equal(t, "test-model:latest", models[1].Name)
 }
 
-// TestDeleteModel validates model deletion.
+// TestDeleteModel validates that deleting a missing model reports ErrModelNotFound.
 func TestDeleteModel(t *testing.T) {
 	rec, err := recorder.New("fixtures/delete_model")
 	require.NoError(t, err)
@@ -36,21 +43,26 @@ func TestDeleteModel(t *testing.T) {
 
 	err = cli.DeleteModel("test-model")
 
-	require.NoError(t, err)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrModelNotFound))
 }
 
 // TestCopyModel validates model copying.
 func TestCopyModel(t *testing.T) {
-	rec, err := recorder.New("fixtures/copy_model")
-	require.NoError(t, err)
-	defer rec.Stop()
-
-	cli := client.NewClient(config.DefaultConfig())
-	cli.HTTPClient.Transport = rec
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "test-model"})
 
-	err = cli.CopyModel("test-model", "test-model-copy")
+	err := srv.Client().CopyModel("test-model", "test-model-copy")
 
 	require.NoError(t, err)
+	var received structures.CopyRequest
+	req, ok := srv.LastRequest("/api/copy")
+	require.True(t, ok)
+	require.NoError(t, req.Decode(&received))
+	require.Equal(t, structures.CopyRequest{Source: "test-model", Destination: "test-model-copy"}, received)
+	models := srv.Models()
+	require.Len(t, models, 2)
+	require.Equal(t, "test-model-copy:latest", models[0].Name)
 }
 
 // TestPullModel validates pulling a model from a remote source.
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"errors"
+	"github.com/SamyRai/ollama-go/modelfile"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+const marioModelfile = `# A playful assistant
+from llama3.2
+PARAMETER temperature 1
+PARAMETER num_ctx 4096
+PARAMETER stop "<|start_header_id|>"
+PARAMETER stop "<|end_header_id|>"
+SYSTEM You are Mario from Super Mario Bros.
+TEMPLATE """{{ if .System }}<|system|>
+{{ .System }}{{ end }}<|user|>
+{{ .Prompt }}"""
+MESSAGE user Is Toronto in Canada?
+MESSAGE assistant "yes, \"it is\""
+LICENSE """MIT
+Copyright"""
+`
+
+// TestParseModelfile validates parsing every instruction into the AST.
+func TestParseModelfile(t *testing.T) {
+	m, err := modelfile.ParseString(marioModelfile)
+	require.NoError(t, err)
+	require.NoError(t, m.Validate())
+
+	require.Equal(t, "llama3.2", m.From())
+	require.Equal(t, "You are Mario from Super Mario Bros.", m.System())
+	require.True(t, strings.HasPrefix(m.Template(), "{{ if .System }}<|system|>\n"))
+	require.Equal(t, []string{"MIT\nCopyright"}, m.Licenses())
+	require.Equal(t, []structures.Message{
+		{Role: "user", Content: "Is Toronto in Canada?"},
+		{Role: "assistant", Content: `yes, "it is"`},
+	}, m.Messages())
+
+	params := m.Parameters()
+	require.Len(t, params, 4)
+	require.Equal(t, modelfile.Instruction{Command: modelfile.Parameter, Name: "stop", Value: "<|start_header_id|>", Line: 5}, params[2])
+	require.Equal(t, 12, m.Instructions[len(m.Instructions)-2].Line)
+
+	opts, err := m.Options()
+	require.NoError(t, err)
+	require.Equal(t, 4096, *opts.NumCtx)
+	require.Equal(t, []string{"<|start_header_id|>", "<|end_header_id|>"}, opts.Stop)
+}
+
+// TestModelfileRoundTrip validates that printing is stable and parses back to the same instructions.
+func TestModelfileRoundTrip(t *testing.T) {
+	m, err := modelfile.ParseString(marioModelfile)
+	require.NoError(t, err)
+
+	printed := m.String()
+	require.True(t, strings.HasPrefix(printed, "FROM llama3.2\nPARAMETER temperature 1\n"))
+
+	again, err := modelfile.ParseString(printed)
+	require.NoError(t, err)
+	require.Equal(t, printed, again.String())
+	require.Equal(t, m.Messages(), again.Messages())
+	require.Equal(t, m.Template(), again.Template())
+	require.Equal(t, m.Licenses(), again.Licenses())
+}
+
+// TestModelfileParseErrors validates syntax errors carry line numbers.
+func TestModelfileParseErrors(t *testing.T) {
+	_, err := modelfile.ParseString("FROM llama3.2\nRUN rm -rf /\n")
+	var parseErr *modelfile.ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, 2, parseErr.Line)
+	require.ErrorIs(t, err, utils.ErrInvalidModelfile)
+
+	_, err = modelfile.ParseString("FROM llama3.2\nSYSTEM \"\"\"unterminated\n")
+	require.ErrorContains(t, err, "unterminated")
+
+	_, err = modelfile.ParseString(`SYSTEM "quoted" trailing`)
+	require.ErrorContains(t, err, "unexpected text")
+}
+
+// TestModelfileValidate validates semantic checks on parameters, roles and FROM.
+func TestModelfileValidate(t *testing.T) {
+	m, err := modelfile.ParseString(`PARAMETER temprature 0.7
+PARAMETER num_ctx lots
+PARAMETER top_p 1.5
+MESSAGE narrator Once upon a time
+`)
+	require.NoError(t, err)
+
+	err = m.Validate()
+	require.ErrorIs(t, err, utils.ErrInvalidModelfile)
+	require.ErrorContains(t, err, "missing FROM")
+	require.ErrorContains(t, err, `line 1: unknown PARAMETER "temprature"`)
+	require.ErrorContains(t, err, "line 2: PARAMETER num_ctx")
+	require.ErrorContains(t, err, `invalid MESSAGE role "narrator"`)
+
+	m, err = modelfile.ParseString("FROM llama3.2\nPARAMETER top_p 1.5\n")
+	require.NoError(t, err)
+	require.ErrorContains(t, m.Validate(), "top_p must be between 0 and 1")
+}
+
+// TestModelfileCreateRequest validates conversion to and from create requests, uploading local files.
+func TestModelfileCreateRequest(t *testing.T) {
+	m, err := modelfile.NewBuilder("./models/mario.gguf").
+		Adapter("./adapters/lora.gguf").
+		Parameter("temperature", 0.5).
+		Parameter("stop", "<|end|>").
+		System("You are Mario.").
+		Message("user", "Hi").
+		Build()
+	require.NoError(t, err)
+
+	var uploaded []string
+	req, err := m.CreateRequest("mario", func(path string) (string, error) {
+		uploaded = append(uploaded, path)
+		return "sha256:" + strings.Repeat("a", 64), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"./models/mario.gguf", "./adapters/lora.gguf"}, uploaded)
+	require.Empty(t, req.From)
+	require.Contains(t, req.Files, "mario.gguf")
+	require.Contains(t, req.Adapters, "lora.gguf")
+	require.Equal(t, map[string]interface{}{"temperature": 0.5, "stop": []string{"<|end|>"}}, req.Parameters)
+	require.Equal(t, "You are Mario.", req.System)
+
+	_, err = m.CreateRequest("mario", nil)
+	require.ErrorContains(t, err, "no blob resolver")
+
+	back, err := modelfile.FromCreateRequest(structures.ModelManagementRequest{
+		Name:       "mario",
+		From:       "llama3.2",
+		Parameters: map[string]interface{}{"stop": []interface{}{"a", "b"}, "num_ctx": float64(2048)},
+		System:     "You are Mario.",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "FROM llama3.2\nPARAMETER num_ctx 2048\nPARAMETER stop \"a\"\nPARAMETER stop \"b\"\nSYSTEM You are Mario.\n", back.String())
+}
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/modelfile"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestFakeServerChat validates scripted, streamed and tool-calling chat replies and request recording.
+func TestFakeServerChat(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2", Capabilities: []string{"completion", "tools"}})
+	cli := srv.Client()
+	ctx := context.Background()
+	req := structures.ChatRequest{Model: "llama3.2", Messages: []structures.Message{{Role: "user", Content: "Say hi"}}}
+
+	resp, err := cli.ChatContext(ctx, req, nil)
+	require.NoError(t, err)
+	require.Equal(t, "Say hi", resp.Message.Content)
+	require.True(t, resp.Done)
+
+	srv.Enqueue("/api/chat", ollamatest.Chunks("Hel", "lo", "!"))
+	req.Stream = true
+	var chunks []string
+	resp, err = cli.ChatContext(ctx, req, func(chunk structures.ChatResponse) {
+		chunks = append(chunks, chunk.Message.Content)
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Hel", "lo", "!", ""}, chunks)
+	require.Equal(t, "Hello!", resp.Message.Content)
+	require.Equal(t, 3, resp.EvalCount)
+
+	srv.Enqueue("/api/chat", ollamatest.ToolCall("get_weather", map[string]interface{}{"city": "Paris"}))
+	req.Stream = false
+	resp, err = cli.ChatContext(ctx, req, nil)
+	require.NoError(t, err)
+	require.Equal(t, "get_weather", resp.Message.ToolCalls[0].Function.Name)
+
+	recorded := srv.RequestsTo("/api/chat")
+	require.Len(t, recorded, 3)
+	var sent structures.ChatRequest
+	require.NoError(t, recorded[1].Decode(&sent))
+	require.True(t, sent.Stream)
+	require.Equal(t, "Say hi", sent.Messages[0].Content)
+	require.Equal(t, []string{"llama3.2:latest"}, srv.Running())
+
+	_, err = cli.ChatContext(ctx, structures.ChatRequest{Model: "missing", Messages: req.Messages}, nil)
+	require.ErrorIs(t, err, utils.ErrModelNotFound)
+}
+
+// TestFakeServerGenerateAndEmbed validates generation, model loading and embeddings.
+func TestFakeServerGenerateAndEmbed(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"}, ollamatest.Model{Name: "nomic-embed-text", EmbeddingLength: 4})
+	cli := srv.Client()
+	ctx := context.Background()
+
+	var pieces []string
+	for chunk, err := range cli.GenerateStream(ctx, structures.CompletionRequest{Model: "llama3.2", Prompt: "one two three"}) {
+		require.NoError(t, err)
+		pieces = append(pieces, chunk.Response)
+	}
+	require.Equal(t, []string{"one ", "two ", "three", ""}, pieces)
+
+	require.NoError(t, cli.UnloadModel(ctx, "llama3.2"))
+	require.Empty(t, srv.Running())
+	require.NoError(t, cli.LoadModel(ctx, "llama3.2", structures.KeepAliveFor(time.Hour)))
+	ps, err := cli.GetRunningProcessesContext(ctx)
+	require.NoError(t, err)
+	require.Len(t, ps.Models, 1)
+	require.WithinDuration(t, time.Now().Add(time.Hour), ps.Models[0].ExpiresAt, time.Minute)
+
+	emb, err := cli.GenerateEmbeddingsContext(ctx, structures.EmbeddingRequest{Model: "nomic-embed-text", Input: []string{"a", "b", "a"}})
+	require.NoError(t, err)
+	require.Len(t, emb.Embeddings, 3)
+	require.Len(t, emb.Embeddings[0], 4)
+	require.Equal(t, emb.Embeddings[0], emb.Embeddings[2])
+	require.NotEqual(t, emb.Embeddings[0], emb.Embeddings[1])
+	require.Equal(t, ollamatest.Embedding("a", 4), emb.Embeddings[0])
+}
+
+// TestFakeServerModels validates tags, show, version, pull, push, create, copy, delete and blobs.
+func TestFakeServerModels(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.Version = "0.9.0"
+	cli := srv.Client()
+	ctx := context.Background()
+
+	version, err := cli.GetVersionContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "0.9.0", version.Version)
+
+	var statuses []string
+	final, err := cli.PullModelProgress(ctx, structures.PullRequest{Model: "llama3.2"}, func(ev client.ProgressEvent) {
+		statuses = append(statuses, ev.Status)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "success", final.Status)
+	require.Contains(t, statuses, "verifying sha256 digest")
+	require.NoError(t, cli.PushModelContext(ctx, "llama3.2"))
+	srv.AddModel(ollamatest.Model{Name: "tiny", Digest: "abc"}) // Shorter than the prefix shown in progress
+	require.NoError(t, cli.PushModelContext(ctx, "tiny"))
+	require.NoError(t, cli.PullModelContext(ctx, "tiny"))
+	require.True(t, srv.RemoveModel("tiny"))
+
+	show, err := cli.ShowModelContext(ctx, structures.ShowModelRequest{Model: "llama3.2"})
+	require.NoError(t, err)
+	require.Equal(t, 4096, show.ContextWindow())
+	require.Equal(t, "llama", show.ModelInfo.Architecture())
+
+	digest, err := cli.UploadBlob(ctx, writeTempFile(t, []byte("adapter weights")), nil)
+	require.NoError(t, err)
+	_, ok := srv.Blob(digest)
+	require.True(t, ok)
+
+	mf, err := modelfile.NewBuilder("llama3.2").System("Be terse.").Parameter("temperature", 0.1).Build()
+	require.NoError(t, err)
+	create, err := mf.CreateRequest("terse", nil)
+	require.NoError(t, err)
+	create.Adapters = map[string]string{"adapter.gguf": digest}
+	require.NoError(t, cli.CreateModelContext(ctx, create))
+	show, err = cli.ShowModelContext(ctx, structures.ShowModelRequest{Model: "terse"})
+	require.NoError(t, err)
+	require.Equal(t, "Be terse.", show.System)
+	require.Equal(t, "temperature 0.1", show.Parameters)
+
+	require.NoError(t, cli.CopyModelContext(ctx, "terse", "terse-copy"))
+	require.NoError(t, cli.DeleteModelContext(ctx, "terse"))
+	require.ErrorIs(t, cli.DeleteModelContext(ctx, "terse"), utils.ErrRequestFailed)
+
+	list, err := cli.ListModelsContext(ctx)
+	require.NoError(t, err)
+	require.Len(t, list.Models, 2)
+	require.Equal(t, "llama3.2:latest", list.Models[0].Name)
+	require.Equal(t, "terse-copy:latest", list.Models[1].Name)
+}
+
+// TestFakeServerFaults validates error injection, latency and dropped connections.
+func TestFakeServerFaults(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+	ctx := context.Background()
+	req := structures.ChatRequest{Model: "llama3.2", Messages: []structures.Message{{Role: "user", Content: "hi"}}}
+
+	retrying := client.NewClient(&config.Config{BaseURL: srv.URL, Retry: config.RetryPolicy{
+		MaxRetries: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	}})
+	srv.Enqueue("/api/chat", ollamatest.Fail(http.StatusServiceUnavailable, "server busy"))
+	resp, err := retrying.ChatContext(ctx, req, nil)
+	require.NoError(t, err)
+	require.Equal(t, "hi", resp.Message.Content)
+	require.Len(t, srv.RequestsTo("/api/chat"), 2)
+
+	srv.Enqueue("/api/chat", ollamatest.Response{Delay: time.Second})
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	_, err = srv.Client().ChatContext(timeout, req, nil)
+	require.Error(t, err)
+
+	srv.Enqueue("/api/chat", ollamatest.Response{Chunks: []string{"partial ", "reply"}, Disconnect: true})
+	req.Stream = true
+	var received int
+	_, err = srv.Client().ChatContext(ctx, req, func(structures.ChatResponse) { received++ })
+	require.Error(t, err)
+	require.Equal(t, 2, received)
+
+	srv.Handle("/api/tags", func(r ollamatest.Request) ollamatest.Response {
+		return ollamatest.Response{Body: structures.ModelListResponse{Models: []structures.ModelInfo{{Name: "scripted"}}}}
+	})
+	list, err := srv.Client().ListModelsContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "scripted", list.Models[0].Name)
+
+	srv.Reset()
+	require.Empty(t, srv.Requests())
+	list, err = srv.Client().ListModelsContext(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "llama3.2:latest", list.Models[0].Name)
+}
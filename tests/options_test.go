@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestOptionsExplicitZero ensures zero values are sent when set and omitted when nil.
+func TestOptionsExplicitZero(t *testing.T) {
+	opts := structures.Options{
+		Temperature: structures.Ptr(0.0),
+		Seed:        structures.Ptr(0),
+		UseMMap:     structures.Ptr(false),
+	}
+
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"temperature":0,"seed":0,"use_mmap":false}`, string(data))
+
+	data, err = json.Marshal(structures.Options{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(data))
+}
+
+// TestOptionsBuilder validates the fluent builder and its validation.
+func TestOptionsBuilder(t *testing.T) {
+	opts, err := structures.NewOptions().
+		Temperature(0).
+		Seed(42).
+		NumCtx(8192).
+		NumPredict(-1).
+		Stop("\n\n", "User:").
+		PenalizeNewline(false).
+		Build()
+
+	require.NoError(t, err)
+	data, err := json.Marshal(opts)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"temperature":0,"seed":42,"num_ctx":8192,"num_predict":-1,"stop":["\n\n","User:"],"penalize_newline":false}`, string(data))
+
+	tests := []struct {
+		name    string
+		builder *structures.OptionsBuilder
+		problem string
+	}{
+		{"Negative temperature", structures.NewOptions().Temperature(-0.1), "temperature"},
+		{"TopP above one", structures.NewOptions().TopP(1.5), "top_p"},
+		{"Zero context", structures.NewOptions().NumCtx(0), "num_ctx"},
+		{"Unknown mirostat mode", structures.NewOptions().Mirostat(3), "mirostat"},
+		{"Empty stop sequence", structures.NewOptions().Stop(""), "stop"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.builder.Build()
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, utils.ErrInvalidOptions))
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+// TestInvalidOptionsNotSent ensures invalid options fail before the request goes out.
+func TestInvalidOptionsNotSent(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	_, err := cli.GenerateCompletion(structures.CompletionRequest{
+		Model:   "llama3.1",
+		Options: structures.Options{TopK: structures.Ptr(-1)},
+	}, nil)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrInvalidOptions))
+	assert.Equal(t, 0, hits)
+}
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newPool starts n fake servers and a pool over them.
+func newPool(t *testing.T, n int) (*client.Pool, []*ollamatest.Server) {
+	t.Helper()
+	var servers []*ollamatest.Server
+	var hosts []string
+	for i := 0; i < n; i++ {
+		srv := ollamatest.NewTestServer(t)
+		servers = append(servers, srv)
+		hosts = append(hosts, srv.URL)
+	}
+	return client.NewPool(config.DefaultConfig(), hosts...), servers
+}
+
+func poolChat(model string) structures.ChatRequest {
+	return structures.ChatRequest{Model: model, Messages: []structures.Message{{Role: "user", Content: "hi"}}}
+}
+
+// TestPoolRouting validates that calls prefer hosts with the model loaded, then on disk, then the least busy.
+func TestPoolRouting(t *testing.T) {
+	pool, servers := newPool(t, 3)
+	ctx := context.Background()
+	servers[0].AddModel(ollamatest.Model{Name: "llama3.2"}, ollamatest.Model{Name: "mistral"})
+	servers[1].AddModel(ollamatest.Model{Name: "llama3.2"})
+	require.NoError(t, servers[1].Client().LoadModel(ctx, "llama3.2", nil))
+	require.NoError(t, pool.Check(ctx))
+
+	hosts := pool.Hosts()
+	require.Equal(t, []string{"llama3.2:latest"}, hosts[1].Loaded)
+	require.Equal(t, []string{"llama3.2:latest", "mistral:latest"}, hosts[0].Installed)
+
+	for i := 0; i < 3; i++ {
+		_, err := pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+		require.NoError(t, err)
+	}
+	require.Len(t, servers[1].RequestsTo("/api/chat"), 3)
+
+	_, err := pool.ChatContext(ctx, poolChat("mistral"), nil)
+	require.NoError(t, err)
+	require.Len(t, servers[0].RequestsTo("/api/chat"), 1)
+	require.Equal(t, []string{"mistral:latest"}, pool.Hosts()[0].Loaded)
+
+	// Calls without a model go to the least used hosts
+	for i := 0; i < 2; i++ {
+		_, err := pool.GetVersionContext(ctx)
+		require.NoError(t, err)
+	}
+	require.Len(t, servers[0].RequestsTo("/api/version"), 1)
+	require.Empty(t, servers[1].RequestsTo("/api/version"))
+
+	// Pinned calls bypass routing
+	_, err = pool.GetVersionContext(client.WithHost(ctx, servers[2].URL))
+	require.NoError(t, err)
+	require.Len(t, servers[2].RequestsTo("/api/version"), 2)
+
+	// Blob uploads without a host go to the first one
+	data := []byte("adapter weights")
+	sum := sha256.Sum256(data)
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+	require.NoError(t, pool.CreateBlob(ctx, digest, bytes.NewReader(data), int64(len(data))))
+	_, ok := servers[0].Blob(digest)
+	require.True(t, ok)
+}
+
+// TestPoolFailover validates retries on other hosts, ejection and readmission by checks.
+func TestPoolFailover(t *testing.T) {
+	pool, servers := newPool(t, 2)
+	ctx := context.Background()
+	for _, srv := range servers {
+		srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+	}
+	require.NoError(t, servers[0].Client().LoadModel(ctx, "llama3.2", nil))
+	require.NoError(t, pool.Check(ctx))
+	var ejected []string
+	pool.EjectAfter = 3
+	pool.OnEject = func(host string, err error) { ejected = append(ejected, host) }
+
+	servers[0].Enqueue("/api/chat", ollamatest.Fail(http.StatusServiceUnavailable, "busy"))
+	resp, err := pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+	require.NoError(t, err)
+	require.Equal(t, "hi", resp.Message.Content)
+	require.Len(t, servers[1].RequestsTo("/api/chat"), 1)
+	require.True(t, pool.Hosts()[0].Healthy)
+
+	// Streams are not retried once chunks have arrived
+	servers[0].Enqueue("/api/chat", ollamatest.Response{Chunks: []string{"a", "b"}, Disconnect: true})
+	stream := poolChat("llama3.2")
+	stream.Stream = true
+	_, err = pool.ChatContext(ctx, stream, nil)
+	require.Error(t, err)
+	require.Len(t, servers[0].RequestsTo("/api/chat"), 2)
+	require.Len(t, servers[1].RequestsTo("/api/chat"), 1)
+
+	// The last host's error is returned once every host has failed
+	servers[1].RemoveModel("llama3.2")
+	servers[0].Enqueue("/api/chat", ollamatest.Fail(http.StatusServiceUnavailable, "busy"))
+	_, err = pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+	require.ErrorIs(t, err, utils.ErrOverloaded)
+	require.Equal(t, []string{servers[0].URL}, ejected)
+
+	// Bad requests are not retried and do not count against the host
+	invalid := poolChat("llama3.2")
+	invalid.Options.TopP = structures.Ptr(2.0)
+	_, err = pool.ChatContext(ctx, invalid, nil)
+	require.ErrorIs(t, err, utils.ErrInvalidOptions)
+	require.Len(t, servers[1].RequestsTo("/api/chat"), 2)
+
+	// Checks eject hosts that are down and readmit those that recovered
+	servers[1].Close()
+	require.Error(t, pool.Check(ctx))
+	require.Equal(t, []string{servers[0].URL, servers[1].URL}, ejected)
+	hosts := pool.Hosts()
+	require.True(t, hosts[0].Healthy)
+	require.False(t, hosts[1].Healthy)
+	_, err = pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+	require.NoError(t, err)
+
+	servers[0].Close()
+	require.Error(t, pool.Check(ctx))
+	_, err = pool.ListModelsContext(ctx)
+	require.ErrorIs(t, err, utils.ErrNoHealthyHosts)
+}
+
+// TestPoolCooldown validates that an ejected host is probed by a call once its cool-down has passed.
+func TestPoolCooldown(t *testing.T) {
+	pool, servers := newPool(t, 2)
+	ctx := context.Background()
+	pool.EjectAfter = 1
+	pool.Cooldown = 50 * time.Millisecond
+
+	servers[0].Enqueue("/api/version", ollamatest.Fail(http.StatusServiceUnavailable, "busy"))
+	_, err := pool.GetVersionContext(ctx)
+	require.NoError(t, err)
+	require.False(t, pool.Hosts()[0].Healthy)
+
+	// Ejected hosts get no calls during the cool-down
+	for i := 0; i < 3; i++ {
+		_, err := pool.GetVersionContext(ctx)
+		require.NoError(t, err)
+	}
+	require.Len(t, servers[0].RequestsTo("/api/version"), 1)
+
+	// A failed probe restarts the cool-down
+	time.Sleep(60 * time.Millisecond)
+	servers[0].Enqueue("/api/version", ollamatest.Fail(http.StatusServiceUnavailable, "busy"))
+	_, err = pool.GetVersionContext(ctx)
+	require.NoError(t, err)
+	_, err = pool.GetVersionContext(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers[0].RequestsTo("/api/version"), 2)
+	require.False(t, pool.Hosts()[0].Healthy)
+
+	// A successful probe readmits the host
+	time.Sleep(60 * time.Millisecond)
+	_, err = pool.GetVersionContext(ctx)
+	require.NoError(t, err)
+	require.Len(t, servers[0].RequestsTo("/api/version"), 3)
+	require.True(t, pool.Hosts()[0].Healthy)
+}
+
+// TestPoolTimeouts validates that pools ignore the default timeout and never eject a host for a timeout.
+func TestPoolTimeouts(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2"})
+	cfg := config.DefaultConfig()
+	cfg.Timeout = 20 * time.Millisecond
+	pool := client.NewPool(cfg, srv.URL)
+	pool.EjectAfter = 1
+	ctx := context.Background()
+
+	srv.Enqueue("/api/chat", ollamatest.Response{Delay: 60 * time.Millisecond})
+	_, err := pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+	require.NoError(t, err)
+
+	pool.Timeout = 20 * time.Millisecond
+	srv.Enqueue("/api/chat", ollamatest.Response{Delay: 60 * time.Millisecond})
+	_, err = pool.ChatContext(ctx, poolChat("llama3.2"), nil)
+	require.ErrorIs(t, err, utils.ErrTimeout)
+	require.True(t, pool.Hosts()[0].Healthy)
+}
+
+// TestPoolEnsureModel validates that an ensure lists and pulls on the same host.
+func TestPoolEnsureModel(t *testing.T) {
+	pool, servers := newPool(t, 2)
+	ctx := context.Background()
+	servers[1].AddModel(ollamatest.Model{Name: "llama3.2"})
+	require.NoError(t, pool.Check(ctx))
+
+	_, err := pool.EnsureModel(ctx, "llama3.2", nil)
+	require.NoError(t, err)
+	require.Len(t, servers[1].RequestsTo("/api/tags"), 2)
+	require.Len(t, servers[0].RequestsTo("/api/tags"), 1)
+
+	// The less used host gets the pull, and is listed before and after it
+	_, err = pool.EnsureModel(ctx, "mistral", nil)
+	require.NoError(t, err)
+	require.Len(t, servers[0].RequestsTo("/api/pull"), 1)
+	require.Len(t, servers[0].RequestsTo("/api/tags"), 3)
+	require.Empty(t, servers[1].RequestsTo("/api/pull"))
+	require.Contains(t, pool.Hosts()[0].Installed, "mistral:latest")
+}
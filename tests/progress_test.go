@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var pullLines = []string{
+	`{"status":"pulling manifest"}`,
+	`{"status":"pulling aaa","digest":"sha256:aaa","total":100,"completed":0}`,
+	`{"status":"pulling aaa","digest":"sha256:aaa","total":100,"completed":50}`,
+	`{"status":"pulling bbb","digest":"sha256:bbb","total":300,"completed":0}`,
+	`{"status":"pulling aaa","digest":"sha256:aaa","total":100,"completed":100}`,
+	`{"status":"pulling bbb","digest":"sha256:bbb","total":300,"completed":300}`,
+	`{"status":"verifying sha256 digest"}`,
+	`{"status":"success"}`,
+}
+
+// TestPullModelProgress validates per-layer and overall progress events.
+func TestPullModelProgress(t *testing.T) {
+	server := ndjsonServer(t, pullLines...)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var events []client.ProgressEvent
+	final, err := cli.PullModelProgress(context.Background(), structures.PullRequest{Model: "llama3.1"}, func(e client.ProgressEvent) {
+		events = append(events, e)
+	})
+	require.NoError(t, err)
+	require.Equal(t, "success", final.Status)
+	require.Len(t, events, len(pullLines))
+
+	require.Nil(t, events[0].Layer)
+	require.Equal(t, "sha256:aaa", events[2].Layer.Digest)
+	require.InDelta(t, 50, events[2].Layer.Percent, 0.001)
+	require.InDelta(t, 50, events[2].Percent, 0.001)
+	require.Equal(t, int64(400), events[3].Total)
+	require.InDelta(t, 12.5, events[3].Percent, 0.001)
+	require.InDelta(t, 100, events[5].Percent, 0.001)
+	require.True(t, events[len(events)-1].Done)
+	require.False(t, events[len(events)-2].Done)
+}
+
+// TestPullModelProgressSendsStream validates that progress calls stream and plain calls do not.
+func TestPullModelProgressSendsStream(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		fmt.Fprintln(w, `{"status":"success"}`)
+	}))
+	defer server.Close()
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	require.NoError(t, cli.PullModel("llama3.1"))
+	_, err := cli.PullModelProgress(context.Background(), structures.PullRequest{Model: "llama3.1", Stream: structures.Ptr(false)}, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, false, bodies[0]["stream"])
+	require.NotContains(t, bodies[1], "stream")
+	require.Equal(t, "llama3.1", bodies[1]["model"])
+}
+
+// TestPushModelProgressError validates that an error reported mid-stream is returned.
+func TestPushModelProgressError(t *testing.T) {
+	server := ndjsonServer(t,
+		`{"status":"retrieving manifest"}`,
+		`{"status":"pushing aaa","digest":"sha256:aaa","total":100,"completed":10}`,
+		`{"error":"unauthorized: authentication required"}`,
+	)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	events := 0
+	final, err := cli.PushModelProgress(context.Background(), structures.PushRequest{Model: "me/model"}, func(client.ProgressEvent) {
+		events++
+	})
+	require.Error(t, err)
+	var apiErr *utils.APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Contains(t, apiErr.Message, "unauthorized")
+	require.Equal(t, 2, events)
+	require.Equal(t, "pushing aaa", final.Status)
+}
+
+// TestCreateModelProgressIncomplete validates that a stream ending before success is an error.
+func TestCreateModelProgressIncomplete(t *testing.T) {
+	server := ndjsonServer(t, `{"status":"reading model metadata"}`, `{"status":"creating system layer"}`)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	final, err := cli.CreateModelProgress(context.Background(), structures.ModelManagementRequest{Name: "mario"}, nil)
+	require.ErrorIs(t, err, utils.ErrInvalidResponse)
+	require.Equal(t, "creating system layer", final.Status)
+}
+
+// TestPullModelProgressEmpty validates that a stream without any status is an error.
+func TestPullModelProgressEmpty(t *testing.T) {
+	server := ndjsonServer(t)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	final, err := cli.PullModelProgress(context.Background(), structures.PullRequest{Model: "llama3.1"}, nil)
+	require.ErrorIs(t, err, utils.ErrInvalidResponse)
+	require.Nil(t, final)
+}
+
+// TestPullModelProgressResume validates that bytes already present on resume do not inflate the rate.
+func TestPullModelProgressResume(t *testing.T) {
+	tracker := client.NewProgressTracker()
+
+	first := tracker.Update(structures.ProgressResponse{Status: "pulling aaa", Digest: "sha256:aaa", Total: 1000, Completed: 900})
+	require.InDelta(t, 90, first.Percent, 0.001)
+	require.Zero(t, first.BytesPerSecond)
+
+	next := tracker.Update(structures.ProgressResponse{Status: "pulling aaa", Digest: "sha256:aaa", Total: 1000, Completed: 950})
+	require.InDelta(t, 95, next.Percent, 0.001)
+	require.Equal(t, int64(950), tracker.Layers()[0].Completed)
+}
+
+// TestPullModelProgressCancel validates that cancelling a pull returns the context error.
+func TestPullModelProgressCancel(t *testing.T) {
+	server := ndjsonServer(t, pullLines...)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := cli.PullModelProgress(ctx, structures.PullRequest{Model: "llama3.1"}, func(e client.ProgressEvent) {
+		if e.Layer != nil {
+			cancel()
+		}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestPullModelProgressTimeout validates that the client's timeout bounds the wait between updates only.
+func TestPullModelProgressTimeout(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.ChunkDelay = 40 * time.Millisecond
+	cli := client.NewClient(&config.Config{BaseURL: srv.URL, Timeout: 100 * time.Millisecond})
+
+	start := time.Now()
+	final, err := cli.PullModelProgress(context.Background(), structures.PullRequest{Model: "llama3.2"}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "success", final.Status)
+	require.Greater(t, time.Since(start), 100*time.Millisecond)
+
+	srv.Enqueue("/api/pull", ollamatest.Response{Lines: []interface{}{
+		structures.ProgressResponse{Status: "pulling manifest"},
+		structures.ProgressResponse{Status: "success"},
+	}, ChunkDelay: time.Second})
+	_, err = cli.PullModelProgress(context.Background(), structures.PullRequest{Model: "llama3.2"}, nil)
+	require.ErrorIs(t, err, utils.ErrTimeout)
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fastRetryPolicy keeps retry tests quick.
+func fastRetryPolicy(maxRetries int) config.RetryPolicy {
+	policy := config.DefaultRetryPolicy()
+	policy.MaxRetries = maxRetries
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+// TestRetryOnUnavailable ensures transient 503s are retried until the server recovers.
+func TestRetryOnUnavailable(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"error":"model is loading"}`)
+			return
+		}
+		fmt.Fprint(w, `{"version":"0.5.11"}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	resp, err := cli.GetVersion()
+
+	require.NoError(t, err)
+	assert.Equal(t, "0.5.11", resp.Version)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+// TestNoRetryOnClientError ensures non-retryable statuses fail on the first attempt.
+func TestNoRetryOnClientError(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":"invalid request"}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	_, err := cli.GetVersion()
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrBadRequest))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+// roundTripFunc scripts the outcome of each attempt at the transport level.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// TestRetryOnlyTransportErrors ensures dropped connections are retried while other failures are not.
+func TestRetryOnlyTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"0.5.11"}`)
+	}))
+	defer server.Close()
+
+	var attempts int
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	cli.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if attempts++; attempts == 1 {
+			return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	resp, err := cli.GetVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "0.5.11", resp.Version)
+	assert.Equal(t, 2, attempts)
+
+	attempts = 0
+	cli.HTTPClient.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, errors.New("proxy misconfigured")
+	})
+	_, err = cli.GetVersion()
+	require.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+// TestNoRetryOfNonIdempotentCalls ensures create, copy and delete are only retried when the policy opts in.
+func TestNoRetryOfNonIdempotentCalls(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := fastRetryPolicy(3)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: policy})
+	require.Error(t, cli.DeleteModel("test-model"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	policy.RetryNonIdempotent = true
+	cli = client.NewClient(&config.Config{BaseURL: server.URL, Retry: policy})
+	require.Error(t, cli.CopyModel("test-model", "test-model-copy"))
+	assert.Equal(t, int32(5), atomic.LoadInt32(&hits))
+}
+
+// TestStreamRetryOnlyBeforeFirstChunk ensures a stream is retried only while nothing was delivered.
+func TestStreamRetryOnlyBeforeFirstChunk(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&hits, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprintln(w, `{"model":"llama3.1","response":"Hi","done":false}`)
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+		}
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL, Retry: fastRetryPolicy(3)})
+	var chunks int
+	_, err := cli.GenerateCompletion(structures.CompletionRequest{Model: "llama3.1", Stream: true}, func(structures.CompletionResponse) {
+		chunks++
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, chunks)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+// TestCircuitBreaker ensures the client fails fast while the server is unhealthy and probes after the cool-down.
+func TestCircuitBreaker(t *testing.T) {
+	var hits int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"version":"0.5.11"}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{
+		BaseURL:        server.URL,
+		CircuitBreaker: config.CircuitBreakerPolicy{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond},
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := cli.GetVersion()
+		require.Error(t, err)
+	}
+
+	_, err := cli.GetVersion()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrCircuitOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	healthy.Store(true)
+	time.Sleep(60 * time.Millisecond)
+
+	resp, err := cli.GetVersion()
+	require.NoError(t, err)
+	assert.Equal(t, "0.5.11", resp.Version)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
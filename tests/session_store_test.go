@@ -0,0 +1,140 @@
+package tests
+
+import (
+	"context"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/session"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// sessionStores returns one store of each kind.
+func sessionStores(t *testing.T) map[string]session.Store {
+	jsonStore, err := session.NewFileStore(t.TempDir(), session.FormatJSON)
+	require.NoError(t, err)
+	jsonlStore, err := session.NewFileStore(t.TempDir(), session.FormatJSONL)
+	require.NoError(t, err)
+	return map[string]session.Store{
+		"memory": session.NewMemoryStore(),
+		"json":   jsonStore,
+		"jsonl":  jsonlStore,
+	}
+}
+
+func storedRecord() *session.Record {
+	return &session.Record{
+		ID:        "support-42",
+		Model:     "llava",
+		System:    "You are a support agent.",
+		Options:   structures.Options{Temperature: structures.Ptr(0.2)},
+		KeepAlive: structures.KeepAliveFor(time.Hour),
+		Metadata:  map[string]string{"user": "ann"},
+		Messages: []structures.Message{
+			{Role: "user", Content: "What is in this picture?", Images: []string{"aGVsbG8="}},
+			{Role: "assistant", ToolCalls: []structures.ToolCall{{Function: structures.ToolCallFunction{
+				Name: "lookup", Arguments: map[string]interface{}{"query": "cat"},
+			}}}},
+			{Role: "tool", Content: `{"status":"success"}`, ToolName: "lookup"},
+			{Role: "assistant", Content: "A cat."},
+		},
+	}
+}
+
+// TestSessionStores validates save, load, list, fork and delete for every store.
+func TestSessionStores(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range sessionStores(t) {
+		t.Run(name, func(t *testing.T) {
+			rec := storedRecord()
+			require.NoError(t, store.Save(ctx, rec))
+			require.Equal(t, int64(1), rec.Version)
+			require.False(t, rec.CreatedAt.IsZero())
+
+			loaded, err := store.Load(ctx, "support-42")
+			require.NoError(t, err)
+			require.Equal(t, rec.Messages, loaded.Messages)
+			require.Equal(t, 0.2, *loaded.Options.Temperature)
+			require.Equal(t, time.Hour, loaded.KeepAlive.Duration())
+			require.Equal(t, "ann", loaded.Metadata["user"])
+
+			replay := loaded.ChatRequest()
+			require.Equal(t, "llava", replay.Model)
+			require.Equal(t, "system", replay.Messages[0].Role)
+			require.Len(t, replay.Messages, 5)
+
+			fork, err := session.Fork(ctx, store, "support-42", "support-42-retry", 1)
+			require.NoError(t, err)
+			require.Equal(t, "support-42", fork.ParentID)
+			require.Len(t, fork.Messages, 1)
+
+			infos, err := store.List(ctx)
+			require.NoError(t, err)
+			require.Len(t, infos, 2)
+			require.Equal(t, "support-42-retry", infos[0].ID)
+			require.Equal(t, 4, infos[1].Messages)
+
+			require.NoError(t, store.Delete(ctx, "support-42"))
+			_, err = store.Load(ctx, "support-42")
+			require.ErrorIs(t, err, utils.ErrSessionNotFound)
+			require.ErrorIs(t, store.Delete(ctx, "support-42"), utils.ErrSessionNotFound)
+
+			require.Error(t, store.Save(ctx, &session.Record{ID: "../escape"}))
+		})
+	}
+}
+
+// TestSessionStoreConflict validates optimistic concurrency between two writers.
+func TestSessionStoreConflict(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range sessionStores(t) {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, store.Save(ctx, storedRecord()))
+
+			first, err := store.Load(ctx, "support-42")
+			require.NoError(t, err)
+			second, err := store.Load(ctx, "support-42")
+			require.NoError(t, err)
+
+			first.Messages = append(first.Messages, structures.Message{Role: "user", Content: "Thanks"})
+			require.NoError(t, store.Save(ctx, first))
+
+			second.Messages = append(second.Messages, structures.Message{Role: "user", Content: "Bye"})
+			require.ErrorIs(t, store.Save(ctx, second), utils.ErrVersionConflict)
+			require.ErrorIs(t, store.Save(ctx, storedRecord()), utils.ErrVersionConflict)
+
+			latest, err := store.Load(ctx, "support-42")
+			require.NoError(t, err)
+			require.Equal(t, int64(2), latest.Version)
+			require.Equal(t, "Thanks", latest.Messages[len(latest.Messages)-1].Content)
+		})
+	}
+}
+
+// TestSessionAutosave validates that an opened session is saved after each reply.
+func TestSessionAutosave(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	store := session.NewMemoryStore()
+	ctx := context.Background()
+
+	s := session.New(cli, "llama3.2", "Be brief.")
+	s.Store = store
+	s.ID = "chat-1"
+	_, err := s.Send(ctx, "Hello")
+	require.NoError(t, err)
+
+	reopened, err := session.Open(ctx, cli, store, "chat-1")
+	require.NoError(t, err)
+	require.Len(t, reopened.Messages, 2)
+	require.Equal(t, 1, reopened.Usage.Requests)
+
+	_, err = reopened.Send(ctx, "Again")
+	require.NoError(t, err)
+	_, err = s.Send(ctx, "Stale writer")
+	require.ErrorIs(t, err, utils.ErrVersionConflict)
+}
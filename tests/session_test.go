@@ -0,0 +1,199 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/ollamatest"
+	"github.com/SamyRai/ollama-go/session"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/tools"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sessionServer answers each chat with "reply N" and usage counts, and summary requests with a fixed summary.
+func sessionServer(t *testing.T, requests *[]structures.ChatRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req structures.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if strings.HasPrefix(req.Messages[0].Content, "Summarize") {
+			fmt.Fprint(w, `{"message":{"role":"assistant","content":"The user introduced themselves as Ann."},"done":true}`)
+			return
+		}
+		*requests = append(*requests, req)
+		fmt.Fprintf(w, `{"message":{"role":"assistant","content":"reply %d"},"done":true,`+
+			`"prompt_eval_count":%d,"eval_count":5,"prompt_eval_duration":1000,"eval_duration":2000}`,
+			len(*requests), 10*len(req.Messages))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// oneTokenPerMessage makes budgets in tests count messages.
+func oneTokenPerMessage(structures.Message) int { return 1 }
+
+// TestSessionHistoryAndUsage validates history bookkeeping and usage reporting.
+func TestSessionHistoryAndUsage(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	s := session.New(client.NewClient(&config.Config{BaseURL: server.URL}), "llama3.2", "Be brief.")
+
+	resp, err := s.Send(context.Background(), "Hi, I am Ann.")
+	require.NoError(t, err)
+	require.Equal(t, "reply 1", resp.Message.Content)
+	_, err = s.Send(context.Background(), "What is my name?")
+	require.NoError(t, err)
+
+	require.Len(t, s.Messages, 4)
+	require.Equal(t, "assistant", s.Messages[3].Role)
+	require.Equal(t, "system", requests[1].Messages[0].Role)
+	require.Len(t, requests[1].Messages, 4)
+
+	require.Equal(t, 2, s.Usage.Requests)
+	require.Equal(t, 20+40, s.Usage.PromptTokens)
+	require.Equal(t, 10, s.Usage.EvalTokens)
+	require.Equal(t, 40, s.Usage.LastPromptTokens)
+	require.Equal(t, 70, s.Usage.TotalTokens())
+	require.Equal(t, int64(4000), s.Usage.EvalDuration.Nanoseconds())
+}
+
+// TestSessionDropOldest validates trimming to the budget, keeping tool results with their calls.
+func TestSessionDropOldest(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	s := session.New(client.NewClient(&config.Config{BaseURL: server.URL}), "llama3.2", "sys")
+	s.Counter = oneTokenPerMessage
+	s.Budget = 4 // System prompt plus three messages
+	s.Messages = []structures.Message{
+		{Role: "user", Content: "u1"},
+		{Role: "assistant", Content: "a1"},
+		{Role: "user", Content: "u2"},
+		{Role: "assistant", ToolCalls: []structures.ToolCall{{Function: structures.ToolCallFunction{Name: "f"}}}},
+		{Role: "tool", Content: "result"},
+		{Role: "assistant", Content: "a2"},
+	}
+
+	_, err := s.Send(context.Background(), "u3")
+	require.NoError(t, err)
+	sent := requests[0].Messages
+	require.Equal(t, []string{"system", "assistant", "user"}, roles(sent))
+	require.Equal(t, "a2", sent[1].Content)
+	require.Len(t, s.Messages, 3)
+}
+
+// TestSessionPinFirst validates that the first user message survives trimming.
+func TestSessionPinFirst(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	s := session.New(client.NewClient(&config.Config{BaseURL: server.URL}), "llama3.2", "")
+	s.Counter = oneTokenPerMessage
+	s.Budget = 3
+	s.Strategy = session.PinFirst{}
+
+	for _, content := range []string{"task", "more", "again"} {
+		_, err := s.Send(context.Background(), content)
+		require.NoError(t, err)
+	}
+	last := requests[len(requests)-1].Messages
+	require.Equal(t, "task", last[0].Content)
+	require.Equal(t, "again", last[len(last)-1].Content)
+	require.Len(t, last, 3)
+}
+
+// TestSessionSummarize validates replacing old messages with a summary.
+func TestSessionSummarize(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	s := session.New(cli, "llama3.2", "")
+	s.Counter = oneTokenPerMessage
+	s.Budget = 4
+	s.Strategy = session.Summarize{Client: cli, Model: "llama3.2", Keep: 2}
+	s.Messages = []structures.Message{
+		{Role: "user", Content: "I am Ann."},
+		{Role: "assistant", Content: "Hello Ann."},
+		{Role: "user", Content: "I like tea."},
+		{Role: "assistant", Content: "Noted."},
+	}
+
+	_, err := s.Send(context.Background(), "What is my name?")
+	require.NoError(t, err)
+	sent := requests[0].Messages
+	require.Len(t, sent, 3)
+	require.Equal(t, "system", sent[0].Role)
+	require.Contains(t, sent[0].Content, "Ann")
+	require.Equal(t, "Noted.", sent[1].Content)
+}
+
+// TestSessionTools validates that tool calls are executed and recorded in the history.
+func TestSessionTools(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := scriptedChatServer(t, []string{
+		`{"role":"assistant","content":"","tool_calls":[{"function":{"name":"getTime","arguments":{}}}]}`,
+		`{"role":"assistant","content":"It is noon."}`,
+	}, &requests)
+	registry := tools.NewRegistry()
+	registry.RegisterTool("getTime", func(structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		return structures.ToolCallResult{Result: "12:00"}, nil
+	})
+
+	s := session.New(client.NewClient(&config.Config{BaseURL: server.URL}), "llama3.1", "sys")
+	s.Tools = registry
+	resp, err := s.Send(context.Background(), "What time is it?")
+	require.NoError(t, err)
+	require.Equal(t, "It is noon.", resp.Message.Content)
+	require.Equal(t, []string{"user", "assistant", "tool", "assistant"}, roles(s.Messages))
+	require.Equal(t, 2, s.Usage.Requests)
+}
+
+// TestSessionErrorKeepsHistory validates that a failed send leaves the history unchanged.
+func TestSessionErrorKeepsHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"model \"nope\" not found"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	s := session.New(client.NewClient(&config.Config{BaseURL: server.URL}), "nope", "")
+	_, err := s.Send(context.Background(), "hello")
+	require.Error(t, err)
+	require.Empty(t, s.Messages)
+}
+
+func roles(messages []structures.Message) []string {
+	out := make([]string, len(messages))
+	for i, msg := range messages {
+		out[i] = msg.Role
+	}
+	return out
+}
+
+// TestSessionBudgetFromModel validates that the budget and the requested context follow the model.
+func TestSessionBudgetFromModel(t *testing.T) {
+	srv := ollamatest.NewTestServer(t)
+	srv.AddModel(ollamatest.Model{Name: "llama3.2", ContextLength: 32768})
+	s := session.New(srv.Client(), "llama3.2", "")
+
+	require.NoError(t, s.SetBudgetFromModel(context.Background(), 0))
+	require.Equal(t, 24576, s.Budget)
+	_, err := s.Send(context.Background(), "hello")
+	require.NoError(t, err)
+
+	var sent structures.ChatRequest
+	req, ok := srv.LastRequest("/api/chat")
+	require.True(t, ok)
+	require.NoError(t, req.Decode(&sent))
+	require.NotNil(t, sent.Options.NumCtx)
+	require.Equal(t, 32768, *sent.Options.NumCtx)
+
+	// An explicit context size is kept and budgeted against
+	s.Options.NumCtx = structures.Ptr(8192)
+	require.NoError(t, s.SetBudgetFromModel(context.Background(), 1024))
+	require.Equal(t, 8192-1024, s.Budget)
+	require.Equal(t, 8192, *s.Options.NumCtx)
+}
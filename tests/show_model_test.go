@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const showModelBody = `{
+  "modelfile": "FROM llava:latest\nPARAMETER num_ctx 8192\n",
+  "parameters": "num_ctx                        8192\nstop                           \"<|start_header_id|>\"\nstop                           \"<|eot_id|>\"",
+  "template": "{{ .Prompt }}",
+  "system": "You are helpful.",
+  "details": {"parent_model": "", "format": "gguf", "family": "llama", "families": ["llama", "clip"], "parameter_size": "7B", "quantization_level": "Q4_0"},
+  "model_info": {
+    "general.architecture": "llama",
+    "general.file_type": 2,
+    "general.parameter_count": 8030261248,
+    "llama.context_length": 131072,
+    "llama.embedding_length": 4096,
+    "llama.block_count": 32,
+    "llama.attention.head_count": 32,
+    "llama.attention.head_count_kv": 8
+  },
+  "projector_info": {"clip.has_vision_encoder": true},
+  "capabilities": ["completion", "tools"],
+  "modified_at": "2025-01-02T15:04:05Z"
+}`
+
+// TestShowModel validates decoding the full /api/show response and its helpers.
+func TestShowModel(t *testing.T) {
+	var got structures.ShowModelRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/show", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		fmt.Fprint(w, showModelBody)
+	}))
+	defer server.Close()
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	resp, err := cli.ShowModel(structures.ShowModelRequest{Model: "llava", Verbose: true})
+	require.NoError(t, err)
+	require.True(t, got.Verbose)
+
+	require.Equal(t, "You are helpful.", resp.System)
+	require.Equal(t, []string{"llama", "clip"}, resp.Details.Families)
+	require.Equal(t, "llama", resp.ModelInfo.Architecture())
+	require.Equal(t, int64(8030261248), resp.ModelInfo.ParameterCount())
+	require.Equal(t, int64(131072), resp.ModelInfo.ContextLength())
+	require.Equal(t, int64(4096), resp.ModelInfo.EmbeddingLength())
+	require.Equal(t, int64(32), resp.ModelInfo.BlockCount())
+	require.Equal(t, int64(8), resp.ModelInfo.HeadCountKV())
+	require.Equal(t, []string{"<|start_header_id|>", "<|eot_id|>"}, resp.ParameterValues()["stop"])
+
+	require.True(t, resp.SupportsTools())
+	require.True(t, resp.SupportsVision()) // From projector_info
+	require.False(t, resp.SupportsEmbedding())
+	require.Equal(t, 8192, resp.ContextWindow())
+
+	resp.Parameters = ""
+	require.Equal(t, 131072, resp.ContextWindow())
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// ndjsonServer streams the given lines, flushing after each one.
+func ndjsonServer(t *testing.T, lines ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, line := range lines {
+			fmt.Fprintln(w, line)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var chatStreamLines = []string{
+	`{"model":"llama3.1","message":{"role":"assistant","content":"The weather"},"done":false}`,
+	`{"model":"llama3.1","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"getWeather","arguments":{"location":"Paris"}}}]},"done":false}`,
+	`{"model":"llama3.1","message":{"role":"assistant","content":" is sunny."},"done":false}`,
+	`{"model":"llama3.1","message":{"role":"assistant","content":""},"done_reason":"stop","done":true,"total_duration":100,"prompt_eval_count":12,"eval_count":7}`,
+}
+
+// TestChatStreamIterator validates iterating over chunks and aggregating them.
+func TestChatStreamIterator(t *testing.T) {
+	server := ndjsonServer(t, chatStreamLines...)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var aggregate client.ChatAggregator
+	chunks := 0
+	for chunk, err := range cli.ChatStream(context.Background(), structures.ChatRequest{Model: "llama3.1"}) {
+		require.NoError(t, err)
+		chunks++
+		aggregate.Add(chunk)
+	}
+
+	resp := aggregate.Response()
+	assert.Equal(t, 4, chunks)
+	assert.Equal(t, "The weather is sunny.", resp.Message.Content)
+	assert.Equal(t, "assistant", resp.Message.Role)
+	require.Len(t, resp.Message.ToolCalls, 1)
+	assert.Equal(t, "getWeather", resp.Message.ToolCalls[0].Function.Name)
+	assert.True(t, resp.Done)
+	assert.Equal(t, "stop", resp.DoneReason)
+	assert.Equal(t, 12, resp.PromptEvalCount)
+	assert.Equal(t, 7, resp.EvalCount)
+}
+
+// TestChatStreamCallbackAggregates ensures the callback API returns the aggregated response.
+func TestChatStreamCallbackAggregates(t *testing.T) {
+	server := ndjsonServer(t, chatStreamLines...)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	resp, err := cli.Chat(structures.ChatRequest{Model: "llama3.1", Stream: true}, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "The weather is sunny.", resp.Message.Content)
+	assert.Len(t, resp.Message.ToolCalls, 1)
+	assert.Equal(t, int64(100), resp.TotalDuration)
+}
+
+// TestGenerateStreamEarlyBreak ensures breaking out of the loop cancels the request.
+func TestGenerateStreamEarlyBreak(t *testing.T) {
+	cancelled := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; ; i++ {
+			if _, err := fmt.Fprintf(w, `{"model":"llama3.1","response":"%d","done":false}`+"\n", i); err != nil {
+				break
+			}
+			w.(http.Flusher).Flush()
+			select {
+			case <-r.Context().Done():
+				close(cancelled)
+				return
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	}))
+	defer server.Close()
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var got []string
+	for chunk, err := range cli.GenerateStream(context.Background(), structures.CompletionRequest{Model: "llama3.1"}) {
+		require.NoError(t, err)
+		got = append(got, chunk.Response)
+		if len(got) == 2 {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"0", "1"}, got)
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not cancelled after breaking out of the loop")
+	}
+}
+
+// TestStreamMalformedChunk ensures undecodable chunks are reported instead of dropped.
+func TestStreamMalformedChunk(t *testing.T) {
+	server := ndjsonServer(t,
+		`{"model":"llama3.1","response":"ok","done":false}`,
+		`{"model":"llama3.1","response":42,"done":false}`,
+	)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	var errs []error
+	chunks := 0
+	for _, err := range cli.GenerateStream(context.Background(), structures.CompletionRequest{Model: "llama3.1"}) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		chunks++
+	}
+
+	assert.Equal(t, 1, chunks)
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], utils.ErrInvalidResponse))
+}
+
+// TestNonStreamingCallsSendStreamFalse ensures "stream": false is on the wire, since the server streams when it
+// is omitted and a non-streaming call would only read the first chunk.
+func TestNonStreamingCallsSendStreamFalse(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintln(w, `{"model":"llama3.1","done":true}`)
+	}))
+	defer server.Close()
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	_, err := cli.Chat(structures.ChatRequest{Model: "llama3.1"}, nil)
+	require.NoError(t, err)
+	_, err = cli.GenerateCompletion(structures.CompletionRequest{Model: "llama3.1"}, nil)
+	require.NoError(t, err)
+
+	require.Len(t, bodies, 2)
+	for _, body := range bodies {
+		assert.Equal(t, false, body["stream"])
+	}
+}
@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/jsonschema"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type forecast struct {
+	City        string   `json:"city" description:"City name"`
+	Unit        string   `json:"unit" jsonschema:"enum=celsius|fahrenheit"`
+	Temperature float64  `json:"temperature" jsonschema:"minimum=-100,maximum=100"`
+	Days        []day    `json:"days" jsonschema:"minItems=1"`
+	Note        *string  `json:"note"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+type day struct {
+	Name string `json:"name"`
+	Rain bool   `json:"rain"`
+}
+
+// TestSchemaFromStruct validates schema generation from struct tags.
+func TestSchemaFromStruct(t *testing.T) {
+	schema, err := jsonschema.For[forecast]()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"city": {"type": "string", "description": "City name"},
+			"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+			"temperature": {"type": "number", "minimum": -100, "maximum": 100},
+			"days": {"type": "array", "minItems": 1, "items": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "rain": {"type": "boolean"}},
+				"required": ["name", "rain"]
+			}},
+			"note": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["city", "unit", "temperature", "days"]
+	}`, string(data))
+}
+
+// TestSchemaValidation validates replies against a generated schema.
+func TestSchemaValidation(t *testing.T) {
+	schema, err := jsonschema.For[forecast]()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		doc     string
+		problem string
+	}{
+		{"Valid", `{"city":"Paris","unit":"celsius","temperature":21.5,"days":[{"name":"Mon","rain":false}]}`, ""},
+		{"Missing property", `{"unit":"celsius","temperature":21.5,"days":[{"name":"Mon","rain":false}]}`, `missing required property "city"`},
+		{"Enum mismatch", `{"city":"Paris","unit":"kelvin","temperature":21.5,"days":[{"name":"Mon","rain":false}]}`, "$.unit"},
+		{"Out of range", `{"city":"Paris","unit":"celsius","temperature":250,"days":[{"name":"Mon","rain":false}]}`, "maximum"},
+		{"Nested type", `{"city":"Paris","unit":"celsius","temperature":21.5,"days":[{"name":"Mon","rain":"no"}]}`, "$.days[0].rain"},
+		{"Not JSON", `The weather is nice`, "not valid JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.ValidateJSON([]byte(tt.doc))
+			if tt.problem == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, utils.ErrSchemaValidation))
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+// TestChatStructuredReprompts ensures an invalid reply is re-prompted and the valid one decoded.
+func TestChatStructuredReprompts(t *testing.T) {
+	replies := []string{
+		`{"city":"Paris","unit":"kelvin","temperature":21.5,"days":[]}`,
+		`{"city":"Paris","unit":"celsius","temperature":21.5,"days":[{"name":"Mon","rain":true}]}`,
+	}
+	var requests []structures.ChatRequest
+	var formats []json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw struct {
+			structures.ChatRequest
+			Format json.RawMessage `json:"format"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		requests = append(requests, raw.ChatRequest)
+		formats = append(formats, raw.Format)
+
+		content, _ := json.Marshal(replies[len(requests)-1])
+		fmt.Fprintf(w, `{"model":"llama3.1","message":{"role":"assistant","content":%s},"done":true}`, content)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	result, err := client.ChatStructured[forecast](context.Background(), cli, structures.ChatRequest{
+		Model:    "llama3.1",
+		Messages: []structures.Message{{Role: "user", Content: "Weather in Paris?"}},
+	}, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, "celsius", result.Unit)
+	require.Len(t, result.Days, 1)
+	assert.True(t, result.Days[0].Rain)
+
+	require.Len(t, requests, 2)
+	assert.Contains(t, string(formats[0]), `"enum":["celsius","fahrenheit"]`)
+	require.Len(t, requests[1].Messages, 3)
+	assert.Equal(t, "assistant", requests[1].Messages[1].Role)
+	assert.Contains(t, requests[1].Messages[2].Content, "$.unit")
+}
+
+// TestGenerateStructuredGivesUp ensures the last validation error is returned once retries run out.
+func TestGenerateStructuredGivesUp(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		fmt.Fprint(w, `{"model":"llama3.1","response":"{\"name\":42}","done":true}`)
+	}))
+	defer server.Close()
+
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	_, err := client.GenerateStructured[day](context.Background(), cli, structures.CompletionRequest{Model: "llama3.1", Prompt: "Name a day"}, 1)
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrSchemaValidation))
+	assert.Equal(t, 2, hits)
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/tools"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedChatServer answers successive chat requests with the given assistant messages, recording each request.
+func scriptedChatServer(t *testing.T, replies []string, requests *[]structures.ChatRequest) *httptest.Server {
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		var req structures.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*requests = append(*requests, req)
+		reply := replies[len(replies)-1]
+		if len(*requests) <= len(replies) {
+			reply = replies[len(*requests)-1]
+		}
+		fmt.Fprintf(w, `{"model":"llama3.1","message":%s,"done":true}`, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestRunnerLoop validates parallel tool execution, error feedback and the transcript.
+func TestRunnerLoop(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := scriptedChatServer(t, []string{
+		`{"role":"assistant","content":"","tool_calls":[
+			{"function":{"name":"getWeather","arguments":{"location":"Paris"}}},
+			{"function":{"name":"getTime","arguments":{"location":"Paris"}}},
+			{"function":{"name":"broken","arguments":{}}}]}`,
+		`{"role":"assistant","content":"It is sunny in Paris at noon."}`,
+	}, &requests)
+
+	// Both tools must be running at the same time for either to finish.
+	var started sync.WaitGroup
+	started.Add(2)
+	waitForBoth := func() error {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			return nil
+		case <-time.After(5 * time.Second):
+			return errors.New("tools did not run in parallel")
+		}
+	}
+
+	registry := tools.NewRegistry()
+	registry.RegisterTool("getWeather", func(args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		if err := waitForBoth(); err != nil {
+			return structures.ToolCallResult{}, err
+		}
+		return structures.ToolCallResult{Result: "sunny in " + args.Arguments["location"].(string)}, nil
+	})
+	registry.RegisterTool("getTime", func(args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		if err := waitForBoth(); err != nil {
+			return structures.ToolCallResult{}, err
+		}
+		return structures.ToolCallResult{Status: "success", Result: "12:00"}, nil
+	})
+	registry.RegisterTool("broken", func(args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		return structures.ToolCallResult{}, errors.New("backend down")
+	})
+
+	var steps int
+	runner := tools.NewRunner(client.NewClient(&config.Config{BaseURL: server.URL}), registry)
+	runner.OnStep = func(tools.Step) { steps++ }
+
+	transcript, err := runner.Run(context.Background(), structures.ChatRequest{
+		Model:    "llama3.1",
+		Messages: []structures.Message{{Role: "user", Content: "Weather and time in Paris?"}},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny in Paris at noon.", transcript.Final.Message.Content)
+	assert.Equal(t, 2, steps)
+	require.Len(t, transcript.Steps, 2)
+	require.Len(t, transcript.Steps[0].Executions, 3)
+	assert.Equal(t, "backend down", transcript.Steps[0].Executions[2].Error)
+
+	// user, assistant (tool calls), three tool messages, final assistant
+	require.Len(t, transcript.Messages, 6)
+	assert.Equal(t, "tool", transcript.Messages[2].Role)
+	assert.Equal(t, "getWeather", transcript.Messages[2].ToolName)
+	assert.JSONEq(t, `{"status":"success","result":"sunny in Paris"}`, transcript.Messages[2].Content)
+	assert.JSONEq(t, `{"status":"failure","result":null,"error":"backend down"}`, transcript.Messages[4].Content)
+
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[1].Messages, 5)
+}
+
+// TestRunnerMaxIterations ensures a model that never stops calling tools is cut off.
+func TestRunnerMaxIterations(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := scriptedChatServer(t, []string{
+		`{"role":"assistant","content":"","tool_calls":[{"function":{"name":"again","arguments":{}}}]}`,
+	}, &requests)
+
+	registry := tools.NewRegistry()
+	registry.RegisterTool("again", func(args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		return structures.ToolCallResult{Result: "ok"}, nil
+	})
+
+	runner := tools.NewRunner(client.NewClient(&config.Config{BaseURL: server.URL}), registry)
+	runner.MaxIterations = 3
+
+	transcript, err := runner.Run(context.Background(), structures.ChatRequest{Model: "llama3.1"})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, utils.ErrMaxIterations))
+	assert.Len(t, transcript.Steps, 3)
+	assert.Len(t, requests, 3)
+}
+
+// TestRunnerTopLevelToolCalls ensures calls sent outside the message are recorded on the assistant message.
+func TestRunnerTopLevelToolCalls(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req structures.ChatRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		if len(requests) == 1 {
+			fmt.Fprint(w, `{"model":"llama3.1","message":{"role":"assistant","content":""},
+				"tool_calls":[{"function":{"name":"getTime","arguments":{}}}],"done":true}`)
+			return
+		}
+		fmt.Fprint(w, `{"model":"llama3.1","message":{"role":"assistant","content":"It is noon."},"done":true}`)
+	}))
+	defer server.Close()
+
+	registry := tools.NewRegistry()
+	registry.RegisterTool("getTime", func(args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		return structures.ToolCallResult{Result: "12:00"}, nil
+	})
+	runner := tools.NewRunner(client.NewClient(&config.Config{BaseURL: server.URL}), registry)
+
+	transcript, err := runner.Run(context.Background(), structures.ChatRequest{
+		Model:    "llama3.1",
+		Messages: []structures.Message{{Role: "user", Content: "What time is it?"}},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	require.Len(t, requests[1].Messages, 3)
+	require.Len(t, requests[1].Messages[1].ToolCalls, 1)
+	assert.Equal(t, "getTime", requests[1].Messages[1].ToolCalls[0].Function.Name)
+	assert.Equal(t, "tool", requests[1].Messages[2].Role)
+	assert.Equal(t, "It is noon.", transcript.Final.Message.Content)
+}
@@ -3,6 +3,7 @@ package tests
 import (
 	"github.com/SamyRai/ollama-go/client"
 	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/jsonschema"
 	"github.com/SamyRai/ollama-go/structures"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,11 +29,15 @@ func TestChatWithTools(t *testing.T) {
 			Function: structures.ToolFunction{
 				Name:        "getWeather",
 				Description: "Retrieve the weather",
-				Parameters: map[string]structures.ToolParam{
-					"location": {
-						Type:        "string",
-						Description: "The location for the weather forecast",
+				Parameters: &jsonschema.Schema{
+					Type: "object",
+					Properties: map[string]*jsonschema.Schema{
+						"location": {
+							Type:        "string",
+							Description: "The location for the weather forecast",
+						},
 					},
+					Required: []string{"location"},
 				},
 			},
 		},
@@ -48,7 +53,7 @@ func TestChatWithTools(t *testing.T) {
 	}
 
 	// Make the API call to Chat
-	resp, err := cli.Chat(req)
+	resp, err := cli.Chat(req, nil)
 
 	// Validate no errors occurred during the request
 	require.NoError(t, err)
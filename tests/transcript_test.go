@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/transcript"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+// pngImage is a base64-encoded PNG signature, enough for media type detection.
+const pngImage = "iVBORw0KGgoAAAANSUhEUg=="
+
+func transcriptMessages() []structures.Message {
+	return []structures.Message{
+		{Role: "system", Content: "You are a travel assistant."},
+		{Role: "user", Content: "Where was this taken, and what is the weather there?", Images: []string{pngImage, "aGVsbG8="}},
+		{Role: "assistant", Content: "Looks like Paris. Let me check.", ToolCalls: []structures.ToolCall{
+			{Function: structures.ToolCallFunction{Name: "get_weather", Arguments: map[string]interface{}{"city": "Paris", "days": float64(2)}}},
+			{Function: structures.ToolCallFunction{Name: "get_time", Arguments: map[string]interface{}{"zone": "Europe/Paris"}}},
+		}},
+		{Role: "tool", Content: `{"status":"success","result":"18°C"}`, ToolName: "get_weather"},
+		{Role: "tool", Content: `{"status":"success","result":"14:05"}`, ToolName: "get_time"},
+		{Role: "assistant", ToolCalls: []structures.ToolCall{
+			{Function: structures.ToolCallFunction{Name: "get_weather", Arguments: map[string]interface{}{"city": "Lyon"}}},
+		}},
+		{Role: "tool", Content: "12°C", ToolName: "get_weather"},
+		{Role: "assistant", Content: "It is 18°C in Paris.\n\n```markdown\n## User\n![image 1](data:image/png;base64,AAAA)\n```"},
+	}
+}
+
+// TestOpenAIRoundTrip validates conversion to and from OpenAI's chat format.
+func TestOpenAIRoundTrip(t *testing.T) {
+	messages := transcriptMessages()
+	data, err := transcript.MarshalOpenAI("gpt-4o", messages)
+	require.NoError(t, err)
+
+	var chat struct {
+		Model    string                   `json:"model"`
+		Messages []map[string]interface{} `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(data, &chat))
+	require.Equal(t, "gpt-4o", chat.Model)
+	parts := chat.Messages[1]["content"].([]interface{})
+	require.Len(t, parts, 3)
+	image := parts[1].(map[string]interface{})["image_url"].(map[string]interface{})
+	require.Equal(t, "data:image/png;base64,"+pngImage, image["url"])
+	calls := chat.Messages[2]["tool_calls"].([]interface{})
+	require.Equal(t, `{"city":"Paris","days":2}`, calls[0].(map[string]interface{})["function"].(map[string]interface{})["arguments"])
+	require.Equal(t, "call_2", chat.Messages[4]["tool_call_id"])
+	require.Equal(t, "call_3", chat.Messages[6]["tool_call_id"])
+
+	decoded, err := transcript.UnmarshalOpenAI(data)
+	require.NoError(t, err)
+	require.Equal(t, messages, decoded)
+
+	bare, err := transcript.UnmarshalOpenAI([]byte(`[
+		{"role":"developer","content":null},
+		{"role":"user","content":[{"type":"text","text":"a"},{"type":"text","text":"b"}]}
+	]`))
+	require.NoError(t, err)
+	require.Equal(t, "system", bare[0].Role)
+	require.Equal(t, "a\nb", bare[1].Content)
+
+	_, err = transcript.UnmarshalOpenAI([]byte(`[{"role":"user","content":[{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]}]`))
+	require.ErrorIs(t, err, utils.ErrBadTranscript)
+}
+
+// TestShareGPTRoundTrip validates conversion to and from ShareGPT JSONL datasets.
+func TestShareGPTRoundTrip(t *testing.T) {
+	messages := transcriptMessages()
+	conv, err := transcript.ToShareGPT(messages)
+	require.NoError(t, err)
+	require.Equal(t, []string{pngImage, "aGVsbG8="}, conv.Images)
+	require.True(t, strings.HasPrefix(conv.Conversations[1].Value, "<image><image>Where"))
+	require.Equal(t, transcript.ShareGPTModel, conv.Conversations[2].From)
+	require.Equal(t, transcript.ShareGPTFunctionCall, conv.Conversations[3].From)
+	require.Equal(t, `{"name":"get_weather","arguments":{"city":"Lyon"}}`, conv.Conversations[6].Value)
+
+	var buf bytes.Buffer
+	short := []structures.Message{{Role: "user", Content: "Hi"}, {Role: "assistant", Content: "Hello"}}
+	require.NoError(t, transcript.WriteShareGPT(&buf, messages, short))
+	require.Equal(t, 2, strings.Count(buf.String(), "\n"))
+	require.Contains(t, buf.String(), `"value":"<image><image>Where`)
+
+	decoded, err := transcript.ReadShareGPT(&buf)
+	require.NoError(t, err)
+	require.Len(t, decoded, 2)
+	require.Equal(t, messages, decoded[0])
+	require.Equal(t, short, decoded[1])
+
+	_, err = transcript.FromShareGPT(transcript.ShareGPTConversation{
+		Conversations: []transcript.ShareGPTTurn{{From: "human", Value: "<image>hi"}},
+	})
+	require.ErrorIs(t, err, utils.ErrBadTranscript)
+	_, err = transcript.ToShareGPT([]structures.Message{{Role: "critic"}})
+	require.ErrorIs(t, err, utils.ErrBadTranscript)
+}
+
+// TestMarkdownRoundTrip validates rendering to and reading from Markdown.
+func TestMarkdownRoundTrip(t *testing.T) {
+	messages := transcriptMessages()
+	md, err := transcript.ToMarkdown(messages)
+	require.NoError(t, err)
+	require.Contains(t, md, "## User\n\nWhere was this taken")
+	require.Contains(t, md, "![image 1](data:image/png;base64,"+pngImage+")")
+	require.Contains(t, md, "**Tool call:** `get_time`\n\n```json\n{\n  \"zone\": \"Europe/Paris\"\n}\n```")
+	require.Contains(t, md, "## Tool: get_weather")
+
+	decoded, err := transcript.FromMarkdown(md)
+	require.NoError(t, err)
+	require.Equal(t, messages, decoded)
+
+	_, err = transcript.FromMarkdown("preamble\n## User\nhi")
+	require.ErrorIs(t, err, utils.ErrBadTranscript)
+	_, err = transcript.FromMarkdown("## Assistant\n\n**Tool call:** `x`\n\n## User\n")
+	require.ErrorIs(t, err, utils.ErrBadTranscript)
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/session"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// TestTreeBranching validates edit, checkout, paths, transcripts and diffs.
+func TestTreeBranching(t *testing.T) {
+	tree := session.NewTree("Be brief.")
+	question := tree.Append(structures.Message{Role: "user", Content: "Name a colour."})
+	first := tree.Append(structures.Message{Role: "assistant", Content: "Red."})
+	tree.Append(structures.Message{Role: "user", Content: "Another?"})
+	tip := tree.Append(structures.Message{Role: "assistant", Content: "Blue."})
+
+	edited, err := tree.Edit(question.ID, "Name a fruit.")
+	require.NoError(t, err)
+	require.Equal(t, edited.ID, tree.Head)
+	require.Equal(t, "user", edited.Message.Role)
+	apple := tree.Append(structures.Message{Role: "assistant", Content: "Apple."})
+
+	siblings, err := tree.Siblings(question.ID)
+	require.NoError(t, err)
+	require.Len(t, siblings, 2)
+	require.Len(t, tree.Leaves(), 2)
+
+	transcript, err := tree.Transcript(tip.ID)
+	require.NoError(t, err)
+	require.Len(t, transcript, 5)
+	require.Equal(t, "system", transcript[0].Role)
+	require.Equal(t, "Blue.", transcript[4].Content)
+
+	req, err := tree.Request(structures.ChatRequest{Model: "llama3.2"}, apple.ID)
+	require.NoError(t, err)
+	require.Equal(t, "llama3.2", req.Model)
+	require.Equal(t, "Name a fruit.", req.Messages[1].Content)
+
+	diff, err := tree.Diff(tip.ID, apple.ID)
+	require.NoError(t, err)
+	require.Empty(t, diff.Common)
+	require.Empty(t, diff.ForkID)
+	require.Len(t, diff.A, 4)
+	require.Len(t, diff.B, 2)
+
+	alt, err := tree.Branch(first.ID, structures.Message{Role: "user", Content: "Why?"})
+	require.NoError(t, err)
+	diff, err = tree.Diff(tip.ID, alt.ID)
+	require.NoError(t, err)
+	require.Equal(t, first.ID, diff.ForkID)
+	require.Len(t, diff.Common, 2)
+	require.Equal(t, "Why?", diff.B[0].Content)
+
+	require.NoError(t, tree.Checkout(tip.ID))
+	require.Error(t, tree.Checkout("missing"))
+	_, err = tree.Branch("missing", structures.Message{Role: "user"})
+	require.Error(t, err)
+
+	data, err := json.Marshal(tree)
+	require.NoError(t, err)
+	var restored session.Tree
+	require.NoError(t, json.Unmarshal(data, &restored))
+	restoredTranscript, err := restored.Transcript(restored.Head)
+	require.NoError(t, err)
+	require.Equal(t, transcript, restoredTranscript)
+}
+
+// TestTreeRegenerate validates that sends extend the checked-out path and regenerations add siblings.
+func TestTreeRegenerate(t *testing.T) {
+	var requests []structures.ChatRequest
+	server := sessionServer(t, &requests)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+	ctx := context.Background()
+	tree := session.NewTree("Be brief.")
+	base := structures.ChatRequest{Model: "llama3.2"}
+
+	reply, err := tree.Send(ctx, cli, base, structures.Message{Role: "user", Content: "Hello"})
+	require.NoError(t, err)
+	require.Equal(t, "reply 1", reply.Message.Content)
+	require.Equal(t, reply.ID, tree.Head)
+
+	again, err := tree.Regenerate(ctx, cli, base)
+	require.NoError(t, err)
+	require.Equal(t, "reply 2", again.Message.Content)
+	require.Equal(t, reply.ParentID, again.ParentID)
+	require.Len(t, requests[1].Messages, 2)
+	require.Equal(t, "Hello", requests[1].Messages[1].Content)
+
+	require.NoError(t, tree.Checkout(reply.ID))
+	_, err = tree.Send(ctx, cli, base, structures.Message{Role: "user", Content: "More"})
+	require.NoError(t, err)
+	require.Equal(t, "reply 1", requests[2].Messages[2].Content)
+	require.Len(t, tree.Leaves(), 2)
+
+	server.Close()
+	head := tree.Head
+	_, err = tree.Send(ctx, cli, base, structures.Message{Role: "user", Content: "Unreachable"})
+	require.Error(t, err)
+	require.Equal(t, head, tree.Head)
+	require.Len(t, tree.Nodes, 5)
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/tools"
+	"github.com/SamyRai/ollama-go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type weatherArgs struct {
+	Location string   `json:"location" description:"City to look up"`
+	Unit     string   `json:"unit,omitempty" jsonschema:"enum=celsius|fahrenheit"`
+	Days     int      `json:"days" jsonschema:"minimum=1,maximum=7"`
+	Coords   *latLong `json:"coords,omitempty"`
+}
+
+type latLong struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+type weatherResult struct {
+	Summary string `json:"summary"`
+	Days    int    `json:"days"`
+}
+
+// TestRegisterTypedTool validates schema derivation, argument validation and decoding for typed tools.
+func TestRegisterTypedTool(t *testing.T) {
+	registry := tools.NewRegistry()
+	err := tools.Register(registry, "getWeather", "Get the weather forecast",
+		func(ctx context.Context, args weatherArgs) (weatherResult, error) {
+			return weatherResult{Summary: "sunny in " + args.Location, Days: args.Days}, nil
+		})
+	require.NoError(t, err)
+
+	defs := registry.Definitions()
+	require.Len(t, defs, 1)
+	data, err := json.Marshal(defs[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "function",
+		"function": {
+			"name": "getWeather",
+			"description": "Get the weather forecast",
+			"parameters": {
+				"type": "object",
+				"properties": {
+					"location": {"type": "string", "description": "City to look up"},
+					"unit": {"type": "string", "enum": ["celsius", "fahrenheit"]},
+					"days": {"type": "integer", "minimum": 1, "maximum": 7},
+					"coords": {"type": "object", "properties": {"lat": {"type": "number"}, "lon": {"type": "number"}}, "required": ["lat", "lon"]}
+				},
+				"required": ["location", "days"]
+			}
+		}
+	}`, string(data))
+
+	result, err := registry.CallToolContext(context.Background(), "getWeather", structures.ToolCallFunction{
+		Name:      "getWeather",
+		Arguments: map[string]interface{}{"location": "Paris", "days": float64(3)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "success", result.Status)
+	assert.Equal(t, weatherResult{Summary: "sunny in Paris", Days: 3}, result.Result)
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		problem string
+	}{
+		{"Missing required", map[string]interface{}{"days": 3}, `missing required property "location"`},
+		{"Out of bounds", map[string]interface{}{"location": "Paris", "days": 30}, "$.days"},
+		{"Wrong nested type", map[string]interface{}{"location": "Paris", "days": 1, "coords": map[string]interface{}{"lat": "north", "lon": 2}}, "$.coords.lat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.CallTool("getWeather", structures.ToolCallFunction{Name: "getWeather", Arguments: tt.args})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, utils.ErrSchemaValidation))
+			assert.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
+
+// TestRegisterTypedToolRejectsNonStruct ensures tool arguments must be objects.
+func TestRegisterTypedToolRejectsNonStruct(t *testing.T) {
+	err := tools.Register(tools.NewRegistry(), "echo", "Echo a string",
+		func(ctx context.Context, args string) (string, error) { return args, nil })
+	require.Error(t, err)
+}
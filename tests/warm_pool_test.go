@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/config"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestKeepAliveJSON validates encoding and decoding typed keep-alive values.
+func TestKeepAliveJSON(t *testing.T) {
+	data, err := json.Marshal(structures.ChatRequest{Model: "m", KeepAlive: structures.KeepAliveFor(10 * time.Minute)})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"keep_alive":"10m0s"`)
+
+	data, err = json.Marshal(structures.ChatRequest{Model: "m", KeepAlive: structures.Ptr(structures.KeepAliveForever)})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"keep_alive":-1`)
+
+	data, err = json.Marshal(structures.ChatRequest{Model: "m", KeepAlive: structures.KeepAliveFor(0)})
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"keep_alive":"0s"`)
+
+	var k structures.KeepAlive
+	require.NoError(t, json.Unmarshal([]byte(`300`), &k))
+	require.Equal(t, 5*time.Minute, k.Duration())
+	require.NoError(t, json.Unmarshal([]byte(`"-5m"`), &k))
+	require.True(t, k.Forever())
+	require.Equal(t, "forever", k.String())
+	require.Error(t, json.Unmarshal([]byte(`"soon"`), &k))
+}
+
+// loadServer records /api/generate load requests and serves /api/ps from running.
+func loadServer(t *testing.T, running string) (*httptest.Server, func() []structures.CompletionRequest) {
+	var mu sync.Mutex
+	var loads []structures.CompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/ps":
+			fmt.Fprint(w, running)
+		case "/api/generate":
+			var req structures.CompletionRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			mu.Lock()
+			loads = append(loads, req)
+			mu.Unlock()
+			fmt.Fprintf(w, `{"model":%q,"response":"","done":true}`, req.Model)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []structures.CompletionRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]structures.CompletionRequest(nil), loads...)
+	}
+}
+
+// TestLoadUnloadModel validates the requests sent to load and unload a model.
+func TestLoadUnloadModel(t *testing.T) {
+	server, loads := loadServer(t, `{"models":[]}`)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	require.NoError(t, cli.LoadModel(context.Background(), "llama3.2", structures.Ptr(structures.KeepAliveForever)))
+	require.NoError(t, cli.UnloadModel(context.Background(), "llama3.2"))
+
+	got := loads()
+	require.Len(t, got, 2)
+	require.True(t, got[0].KeepAlive.Forever())
+	require.Empty(t, got[0].Prompt)
+	require.Equal(t, time.Duration(0), got[1].KeepAlive.Duration())
+}
+
+// TestWarmPoolSync validates that only missing or soon-expiring models are reloaded.
+func TestWarmPoolSync(t *testing.T) {
+	running := fmt.Sprintf(`{"models":[
+		{"name":"llama3.2:latest","expires_at":%q},
+		{"name":"qwen2.5:7b","expires_at":%q}
+	]}`, time.Now().Add(time.Hour).Format(time.RFC3339), time.Now().Add(10*time.Second).Format(time.RFC3339))
+	server, loads := loadServer(t, running)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	pool := client.NewWarmPool(cli, "llama3.2", "qwen2.5:7b", "mistral")
+	pool.KeepAlive = 30 * time.Minute
+	var loaded []string
+	pool.OnLoad = func(model string, err error) {
+		require.NoError(t, err)
+		loaded = append(loaded, model)
+	}
+	require.NoError(t, pool.Sync(context.Background()))
+
+	sort.Strings(loaded)
+	require.Equal(t, []string{"mistral", "qwen2.5:7b"}, loaded)
+	for _, req := range loads() {
+		require.Equal(t, 30*time.Minute, req.KeepAlive.Duration())
+	}
+}
+
+// TestWarmPoolRun validates that Run keeps syncing until its context is cancelled.
+func TestWarmPoolRun(t *testing.T) {
+	server, loads := loadServer(t, `{"models":[]}`)
+	cli := client.NewClient(&config.Config{BaseURL: server.URL})
+
+	pool := client.NewWarmPool(cli, "llama3.2")
+	pool.Interval = 10 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, pool.Run(ctx), context.DeadlineExceeded)
+	require.GreaterOrEqual(t, len(loads()), 3)
+}
@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/client"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"sync"
+	"time"
+)
+
+// DefaultMaxIterations bounds how many chat round trips a Runner makes by default.
+const DefaultMaxIterations = 10
+
+// Runner drives a chat, executing the tools the model requests, until the model answers without tool calls.
+type Runner struct {
+	Client        *client.OllamaClient
+	Registry      *ToolRegistry
+	MaxIterations int        // Maximum chat round trips (DefaultMaxIterations if zero).
+	OnStep        func(Step) // Optional: Called after each step completes.
+}
+
+// Step records one chat round trip and the tools executed in response to it.
+type Step struct {
+	Response   structures.ChatResponse `json:"response"`             // The model's reply.
+	Executions []ToolExecution         `json:"executions,omitempty"` // Tools run for the reply's tool calls, in call order.
+}
+
+// ToolExecution records a single tool call and its outcome.
+type ToolExecution struct {
+	Call     structures.ToolCall       `json:"call"`            // The tool call requested by the model.
+	Result   structures.ToolCallResult `json:"result"`          // The result fed back to the model.
+	Error    string                    `json:"error,omitempty"` // Error returned by the tool, if any.
+	Duration time.Duration             `json:"duration"`        // Time spent executing the tool.
+}
+
+// Transcript is the full record of a Runner session.
+type Transcript struct {
+	Messages []structures.Message    `json:"messages"` // Conversation including assistant and tool messages.
+	Steps    []Step                  `json:"steps"`    // Every round trip in order.
+	Final    structures.ChatResponse `json:"final"`    // The last reply from the model.
+}
+
+// NewRunner creates a Runner with the default iteration limit.
+func NewRunner(c *client.OllamaClient, registry *ToolRegistry) *Runner {
+	return &Runner{Client: c, Registry: registry, MaxIterations: DefaultMaxIterations}
+}
+
+// Run sends req and keeps answering the model's tool calls until it replies without any.
+// If req.Tools is empty, the registry's tool definitions are sent.
+// Tool errors are reported back to the model as tool messages rather than aborting the run.
+// When MaxIterations is reached the transcript so far is returned with utils.ErrMaxIterations.
+func (r *Runner) Run(ctx context.Context, req structures.ChatRequest) (*Transcript, error) {
+	maxIterations := r.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxIterations
+	}
+
+	if len(req.Tools) == 0 {
+		req.Tools = r.Registry.Definitions()
+	}
+
+	transcript := &Transcript{Messages: append([]structures.Message(nil), req.Messages...)}
+	for i := 0; i < maxIterations; i++ {
+		req.Messages = transcript.Messages
+		resp, err := r.Client.ChatContext(ctx, req, nil)
+		if err != nil {
+			return transcript, err
+		}
+
+		step := Step{Response: *resp}
+		transcript.Final = *resp
+
+		// Calls may also arrive at the top level; the assistant message must carry them all for the tool
+		// results that follow it to make sense to the model
+		calls := append(append([]structures.ToolCall(nil), resp.Message.ToolCalls...), resp.ToolCalls...)
+		msg := resp.Message
+		msg.ToolCalls = calls
+		transcript.Messages = append(transcript.Messages, msg)
+
+		if len(calls) == 0 {
+			transcript.Steps = append(transcript.Steps, step)
+			r.notify(step)
+			return transcript, nil
+		}
+
+		step.Executions = r.execute(ctx, calls)
+		for _, exec := range step.Executions {
+			transcript.Messages = append(transcript.Messages, toolMessage(exec))
+		}
+		transcript.Steps = append(transcript.Steps, step)
+		r.notify(step)
+	}
+
+	return transcript, fmt.Errorf("%w (%d)", utils.ErrMaxIterations, maxIterations)
+}
+
+// execute runs the tool calls in parallel, returning their executions in call order.
+func (r *Runner) execute(ctx context.Context, calls []structures.ToolCall) []ToolExecution {
+	executions := make([]ToolExecution, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			executions[i] = r.call(ctx, call)
+		}()
+	}
+	wg.Wait()
+	return executions
+}
+
+// call runs a single tool, turning errors and panics into a failure result.
+func (r *Runner) call(ctx context.Context, call structures.ToolCall) (exec ToolExecution) {
+	exec.Call = call
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			exec.Error = fmt.Sprintf("tool %s panicked: %v", call.Function.Name, p)
+			exec.Result = structures.ToolCallResult{Status: "failure", Error: exec.Error}
+		}
+		exec.Duration = time.Since(start)
+	}()
+
+	if err := ctx.Err(); err != nil {
+		exec.Error = err.Error()
+		exec.Result = structures.ToolCallResult{Status: "failure", Error: exec.Error}
+		return exec
+	}
+
+	result, err := r.Registry.CallToolContext(ctx, call.Function.Name, call.Function)
+	if err != nil {
+		exec.Error = err.Error()
+		result = structures.ToolCallResult{Status: "failure", Error: exec.Error}
+	}
+	if result.Status == "" {
+		result.Status = "success"
+	}
+	exec.Result = result
+	return exec
+}
+
+// notify reports a completed step to OnStep, if set.
+func (r *Runner) notify(step Step) {
+	if r.OnStep != nil {
+		r.OnStep(step)
+	}
+}
+
+// toolMessage converts a tool execution into the "tool" message fed back to the model.
+func toolMessage(exec ToolExecution) structures.Message {
+	content, err := json.Marshal(exec.Result)
+	if err != nil {
+		content, _ = json.Marshal(structures.ToolCallResult{Status: "failure", Error: err.Error()})
+	}
+	return structures.Message{
+		Role:     "tool",
+		Content:  string(content),
+		ToolName: exec.Call.Function.Name,
+	}
+}
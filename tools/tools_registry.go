@@ -1,38 +1,82 @@
 package tools
 
 import (
+	"context"
 	"errors"
 	"github.com/SamyRai/ollama-go/structures"
+	"sort"
 	"sync"
 )
 
+// ToolHandler executes a tool call. The context is cancelled when the caller gives up.
+type ToolHandler func(ctx context.Context, args structures.ToolCallFunction) (structures.ToolCallResult, error)
+
 // ToolRegistry manages registered tools with strict function definitions.
 type ToolRegistry struct {
 	mu    sync.RWMutex
-	tools map[string]func(args structures.ToolCallFunction) (structures.ToolCallResult, error)
+	tools map[string]ToolHandler
+	defs  map[string]structures.Tool
 }
 
 // NewRegistry initializes a strict tool registry.
 func NewRegistry() *ToolRegistry {
 	return &ToolRegistry{
-		tools: make(map[string]func(args structures.ToolCallFunction) (structures.ToolCallResult, error)),
+		tools: make(map[string]ToolHandler),
+		defs:  make(map[string]structures.Tool),
 	}
 }
 
 // RegisterTool registers a tool function by strict definition.
 func (r *ToolRegistry) RegisterTool(name string, handler func(args structures.ToolCallFunction) (structures.ToolCallResult, error)) {
+	r.register(name, nil, func(ctx context.Context, args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		return handler(args)
+	})
+}
+
+// RegisterDefinedTool registers a handler together with the definition advertised to the model.
+func (r *ToolRegistry) RegisterDefinedTool(tool structures.Tool, handler ToolHandler) {
+	r.register(tool.Function.Name, &tool, handler)
+}
+
+// register stores a handler and, if given, its definition.
+func (r *ToolRegistry) register(name string, def *structures.Tool, handler ToolHandler) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.tools[name] = handler
+	if def != nil {
+		r.defs[name] = *def
+	} else {
+		delete(r.defs, name)
+	}
+}
+
+// Definitions returns the definitions of all tools registered with one, sorted by name,
+// ready to be sent as ChatRequest.Tools.
+func (r *ToolRegistry) Definitions() []structures.Tool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	defs := make([]structures.Tool, 0, len(r.defs))
+	for _, def := range r.defs {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Function.Name < defs[j].Function.Name })
+	return defs
 }
 
 // CallTool executes a registered tool function.
 func (r *ToolRegistry) CallTool(name string, args structures.ToolCallFunction) (structures.ToolCallResult, error) {
+	return r.CallToolContext(context.Background(), name, args)
+}
+
+// CallToolContext is like CallTool but passes ctx to the handler.
+func (r *ToolRegistry) CallToolContext(ctx context.Context, name string, args structures.ToolCallFunction) (structures.ToolCallResult, error) {
 	r.mu.RLock()
-	defer r.mu.RUnlock()
+	fn, exists := r.tools[name]
+	r.mu.RUnlock()
 
-	if fn, exists := r.tools[name]; exists {
-		return fn(args)
+	if exists {
+		return fn(ctx, args)
 	}
 	return structures.ToolCallResult{}, errors.New("tool not registered: " + name)
 }
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/jsonschema"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+)
+
+// Register adds a typed tool to the registry. The parameter schema advertised to the model is derived
+// from the struct type A (see jsonschema.For for the supported tags). Arguments sent by the model are
+// validated against that schema and decoded into A before fn runs; its result is returned as the tool result.
+//
+//	tools.Register(registry, "getWeather", "Get the current weather",
+//		func(ctx context.Context, args WeatherArgs) (WeatherResult, error) { ... })
+func Register[A, R any](r *ToolRegistry, name, description string, fn func(ctx context.Context, args A) (R, error)) error {
+	schema, err := jsonschema.For[A]()
+	if err != nil {
+		return err
+	}
+	if schema.Type != "object" {
+		return fmt.Errorf("tool %s: arguments must be a struct, got schema type %q", name, schema.Type)
+	}
+
+	tool := structures.Tool{
+		Type: "function",
+		Function: structures.ToolFunction{
+			Name:        name,
+			Description: description,
+			Parameters:  schema,
+		},
+	}
+	r.RegisterDefinedTool(tool, func(ctx context.Context, call structures.ToolCallFunction) (structures.ToolCallResult, error) {
+		args, err := decodeArguments[A](schema, call.Arguments)
+		if err != nil {
+			return structures.ToolCallResult{}, fmt.Errorf("tool %s: %w", name, err)
+		}
+		result, err := fn(ctx, args)
+		if err != nil {
+			return structures.ToolCallResult{}, err
+		}
+		return structures.ToolCallResult{Status: "success", Result: result}, nil
+	})
+	return nil
+}
+
+// decodeArguments validates the model's arguments against schema and decodes them into A.
+func decodeArguments[A any](schema *jsonschema.Schema, arguments map[string]interface{}) (A, error) {
+	var args A
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return args, fmt.Errorf("%w: %v", utils.ErrSchemaValidation, err)
+	}
+	if err := schema.ValidateJSON(data); err != nil {
+		return args, err
+	}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return args, fmt.Errorf("%w: %v", utils.ErrSchemaValidation, err)
+	}
+	return args, nil
+}
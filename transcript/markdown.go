@@ -0,0 +1,139 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"regexp"
+	"strings"
+)
+
+// Markdown layout patterns, matched outside code blocks only.
+var (
+	headingPattern  = regexp.MustCompile(`^## (System|User|Assistant|Tool)(?:: (.+))?$`)
+	imagePattern    = regexp.MustCompile(`^!\[image \d+\]\((data:[^)]+)\)$`)
+	toolCallPattern = regexp.MustCompile("^\\*\\*Tool call:\\*\\* `([^`]+)`$")
+)
+
+// ToMarkdown renders messages as Markdown: a "## Role" heading per message ("## Tool: name" for tool
+// results), followed by the content, images as inline data URLs and tool calls as JSON code blocks.
+func ToMarkdown(messages []structures.Message) (string, error) {
+	var b strings.Builder
+	for i, msg := range messages {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		heading := "## " + roleTitle(msg.Role)
+		if msg.Role == "tool" && msg.ToolName != "" {
+			heading += ": " + msg.ToolName
+		}
+		b.WriteString(heading + "\n")
+
+		if msg.Content != "" {
+			b.WriteString("\n" + msg.Content + "\n")
+		}
+		for j, image := range msg.Images {
+			fmt.Fprintf(&b, "\n![image %d](%s)\n", j+1, imageDataURL(image))
+		}
+		for _, call := range msg.ToolCalls {
+			args, err := json.MarshalIndent(call.Function.Arguments, "", "  ")
+			if err != nil {
+				return "", fmt.Errorf("%w: message %d: arguments of %s: %v", utils.ErrBadTranscript, i, call.Function.Name, err)
+			}
+			fmt.Fprintf(&b, "\n**Tool call:** `%s`\n\n```json\n%s\n```\n", call.Function.Name, args)
+		}
+	}
+	return b.String(), nil
+}
+
+// FromMarkdown reads messages back from ToMarkdown's layout. Headings, image lines and tool calls inside
+// fenced code blocks are treated as content. Whitespace around message content is not preserved.
+func FromMarkdown(src string) ([]structures.Message, error) {
+	var out []structures.Message
+	var content []string
+	var call *structures.ToolCall // Tool call waiting for its arguments block
+	inCode := false
+
+	flush := func() {
+		if len(out) > 0 {
+			out[len(out)-1].Content = strings.TrimSpace(strings.Join(content, "\n"))
+		}
+		content = nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	for n := 0; n < len(lines); n++ {
+		line := lines[n]
+		if m := headingPattern.FindStringSubmatch(line); m != nil && !inCode {
+			if call != nil {
+				return nil, fmt.Errorf("%w: line %d: tool call %s has no arguments", utils.ErrBadTranscript, n+1, call.Function.Name)
+			}
+			flush()
+			out = append(out, structures.Message{Role: strings.ToLower(m[1]), ToolName: m[2]})
+			continue
+		}
+		if len(out) == 0 {
+			if strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("%w: line %d: text before the first message heading", utils.ErrBadTranscript, n+1)
+			}
+			continue
+		}
+
+		if call != nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if line != "```json" {
+				return nil, fmt.Errorf("%w: line %d: expected the arguments of %s", utils.ErrBadTranscript, n+1, call.Function.Name)
+			}
+			end := n + 1
+			for end < len(lines) && lines[end] != "```" {
+				end++
+			}
+			if end == len(lines) {
+				return nil, fmt.Errorf("%w: line %d: unterminated arguments block", utils.ErrBadTranscript, n+1)
+			}
+			if err := json.Unmarshal([]byte(strings.Join(lines[n+1:end], "\n")), &call.Function.Arguments); err != nil {
+				return nil, fmt.Errorf("%w: line %d: arguments of %s: %v", utils.ErrBadTranscript, n+2, call.Function.Name, err)
+			}
+			msg := &out[len(out)-1]
+			msg.ToolCalls = append(msg.ToolCalls, *call)
+			call = nil
+			n = end
+			continue
+		}
+
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		} else if !inCode {
+			if m := imagePattern.FindStringSubmatch(line); m != nil {
+				image, err := parseDataURL(m[1])
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", n+1, err)
+				}
+				msg := &out[len(out)-1]
+				msg.Images = append(msg.Images, image)
+				continue
+			}
+			if m := toolCallPattern.FindStringSubmatch(line); m != nil {
+				call = &structures.ToolCall{Function: structures.ToolCallFunction{Name: m[1]}}
+				continue
+			}
+		}
+		content = append(content, line)
+	}
+	if call != nil {
+		return nil, fmt.Errorf("%w: tool call %s has no arguments", utils.ErrBadTranscript, call.Function.Name)
+	}
+	flush()
+	return out, nil
+}
+
+// roleTitle capitalizes a role for use in a heading.
+func roleTitle(role string) string {
+	if role == "" {
+		return role
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
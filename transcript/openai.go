@@ -0,0 +1,204 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"strings"
+)
+
+// OpenAIChat is a conversation in OpenAI's chat format, as sent to /v1/chat/completions or stored one per
+// line in an OpenAI fine-tuning dataset.
+type OpenAIChat struct {
+	Model    string          `json:"model,omitempty"`
+	Messages []OpenAIMessage `json:"messages"`
+}
+
+// OpenAIMessage is a message in OpenAI's chat format.
+type OpenAIMessage struct {
+	Role       string           `json:"role"` // "system", "user", "assistant" or "tool".
+	Content    OpenAIContent    `json:"content"`
+	Name       string           `json:"name,omitempty"`
+	ToolCalls  []OpenAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"` // For "tool" messages: The call being answered.
+}
+
+// OpenAIContent is message content: either plain text or a list of text and image parts.
+type OpenAIContent struct {
+	Text  string
+	Parts []OpenAIContentPart // Used instead of Text when not nil.
+}
+
+// OpenAIContentPart is one part of multi-part message content.
+type OpenAIContentPart struct {
+	Type     string          `json:"type"` // "text" or "image_url".
+	Text     string          `json:"text,omitempty"`
+	ImageURL *OpenAIImageURL `json:"image_url,omitempty"`
+}
+
+// OpenAIImageURL references an image, here always as a base64 data URL.
+type OpenAIImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// OpenAIToolCall is a function call made by the model.
+type OpenAIToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"` // Always "function".
+	Function OpenAIFunctionCall `json:"function"`
+}
+
+// OpenAIFunctionCall names the function and carries its arguments as a JSON string.
+type OpenAIFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+// MarshalJSON encodes the content as a string, or as an array of parts if Parts is set.
+func (c OpenAIContent) MarshalJSON() ([]byte, error) {
+	if c.Parts != nil {
+		return json.Marshal(c.Parts)
+	}
+	return json.Marshal(c.Text)
+}
+
+// UnmarshalJSON accepts a string, an array of parts or null.
+func (c *OpenAIContent) UnmarshalJSON(data []byte) error {
+	*c = OpenAIContent{}
+	switch data = bytes.TrimSpace(data); {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+	case len(data) > 0 && data[0] == '[':
+		return json.Unmarshal(data, &c.Parts)
+	default:
+		return json.Unmarshal(data, &c.Text)
+	}
+}
+
+// ToOpenAI converts messages to OpenAI's format. Images become data URL parts, and tool calls are given
+// IDs that the following tool messages refer to.
+func ToOpenAI(messages []structures.Message) ([]OpenAIMessage, error) {
+	out := make([]OpenAIMessage, 0, len(messages))
+	pending := callQueue{}
+	calls := 0
+	for _, msg := range messages {
+		converted := OpenAIMessage{Role: msg.Role, Content: OpenAIContent{Text: msg.Content}}
+		if len(msg.Images) > 0 {
+			converted.Content.Parts = []OpenAIContentPart{}
+			if msg.Content != "" {
+				converted.Content.Parts = append(converted.Content.Parts, OpenAIContentPart{Type: "text", Text: msg.Content})
+			}
+			for _, image := range msg.Images {
+				converted.Content.Parts = append(converted.Content.Parts, OpenAIContentPart{
+					Type: "image_url", ImageURL: &OpenAIImageURL{URL: imageDataURL(image)},
+				})
+			}
+		}
+
+		for _, call := range msg.ToolCalls {
+			args, err := json.Marshal(call.Function.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("%w: arguments of %s: %v", utils.ErrBadTranscript, call.Function.Name, err)
+			}
+			calls++
+			id := fmt.Sprintf("call_%d", calls)
+			pending.push(call.Function.Name, id)
+			converted.ToolCalls = append(converted.ToolCalls, OpenAIToolCall{
+				ID: id, Type: "function", Function: OpenAIFunctionCall{Name: call.Function.Name, Arguments: string(args)},
+			})
+		}
+
+		if msg.Role == "tool" {
+			if id, ok := pending.pop(msg.ToolName); ok {
+				converted.ToolCallID = id
+			} else {
+				converted.Name = msg.ToolName // No matching call; keep the name at least
+			}
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+// FromOpenAI converts messages from OpenAI's format. Images must be base64 data URLs; "developer" messages
+// become system messages and legacy "function" messages become tool messages.
+func FromOpenAI(messages []OpenAIMessage) ([]structures.Message, error) {
+	out := make([]structures.Message, 0, len(messages))
+	names := map[string]string{} // Tool call ID to function name
+	for i, msg := range messages {
+		converted := structures.Message{Role: msg.Role, Content: msg.Content.Text}
+		switch msg.Role {
+		case "developer":
+			converted.Role = "system"
+		case "function":
+			converted.Role = "tool"
+		}
+
+		var text []string
+		for _, part := range msg.Content.Parts {
+			switch {
+			case part.Type == "text":
+				text = append(text, part.Text)
+			case part.Type == "image_url" && part.ImageURL != nil:
+				image, err := parseDataURL(part.ImageURL.URL)
+				if err != nil {
+					return nil, fmt.Errorf("message %d: %w", i, err)
+				}
+				converted.Images = append(converted.Images, image)
+			default:
+				return nil, fmt.Errorf("%w: message %d: unsupported content part %q", utils.ErrBadTranscript, i, part.Type)
+			}
+		}
+		if msg.Content.Parts != nil {
+			converted.Content = strings.Join(text, "\n")
+		}
+
+		for _, call := range msg.ToolCalls {
+			args := map[string]interface{}{}
+			if strings.TrimSpace(call.Function.Arguments) != "" {
+				if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+					return nil, fmt.Errorf("%w: message %d: arguments of %s: %v", utils.ErrBadTranscript, i, call.Function.Name, err)
+				}
+			}
+			names[call.ID] = call.Function.Name
+			converted.ToolCalls = append(converted.ToolCalls, structures.ToolCall{
+				Function: structures.ToolCallFunction{Name: call.Function.Name, Arguments: args},
+			})
+		}
+
+		if converted.Role == "tool" {
+			converted.ToolName = msg.Name
+			if name, ok := names[msg.ToolCallID]; ok {
+				converted.ToolName = name
+			}
+		}
+		out = append(out, converted)
+	}
+	return out, nil
+}
+
+// MarshalOpenAI encodes messages as an OpenAI chat document for model (which may be empty).
+func MarshalOpenAI(model string, messages []structures.Message) ([]byte, error) {
+	converted, err := ToOpenAI(messages)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(OpenAIChat{Model: model, Messages: converted})
+}
+
+// UnmarshalOpenAI decodes an OpenAI chat document, or a bare array of OpenAI messages.
+func UnmarshalOpenAI(data []byte) ([]structures.Message, error) {
+	var chat OpenAIChat
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &chat.Messages); err != nil {
+			return nil, fmt.Errorf("%w: %v", utils.ErrBadTranscript, err)
+		}
+	} else if err := json.Unmarshal(data, &chat); err != nil {
+		return nil, fmt.Errorf("%w: %v", utils.ErrBadTranscript, err)
+	}
+	return FromOpenAI(chat.Messages)
+}
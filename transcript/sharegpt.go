@@ -0,0 +1,216 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SamyRai/ollama-go/structures"
+	"github.com/SamyRai/ollama-go/utils"
+	"io"
+	"strings"
+)
+
+// ShareGPT turn senders, following the LLaMA-Factory convention for tool calls.
+const (
+	ShareGPTSystem       = "system"
+	ShareGPTHuman        = "human"
+	ShareGPTModel        = "gpt"
+	ShareGPTFunctionCall = "function_call" // Assistant tool calls, as a JSON object or array of {name, arguments}.
+	ShareGPTObservation  = "observation"   // Tool result.
+)
+
+// ImagePlaceholder marks where an image belongs in a ShareGPT turn.
+const ImagePlaceholder = "<image>"
+
+// ShareGPTConversation is one record of a ShareGPT dataset. Images are stored base64-encoded in order of
+// appearance, each marked by an ImagePlaceholder at the start of the turn it belongs to.
+type ShareGPTConversation struct {
+	Conversations []ShareGPTTurn `json:"conversations"`
+	Images        []string       `json:"images,omitempty"`
+}
+
+// ShareGPTTurn is a single turn of a ShareGPT conversation.
+type ShareGPTTurn struct {
+	From  string `json:"from"`
+	Value string `json:"value"`
+}
+
+// shareGPTCall is the value of a function_call turn.
+type shareGPTCall struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments"`
+}
+
+// ToShareGPT converts messages to a ShareGPT conversation. An assistant message with both text and tool
+// calls becomes a gpt turn followed by a function_call turn.
+func ToShareGPT(messages []structures.Message) (ShareGPTConversation, error) {
+	var conv ShareGPTConversation
+	turn := func(from string, msg structures.Message) {
+		conv.Images = append(conv.Images, msg.Images...)
+		value := strings.Repeat(ImagePlaceholder, len(msg.Images)) + msg.Content
+		conv.Conversations = append(conv.Conversations, ShareGPTTurn{From: from, Value: value})
+	}
+
+	for i, msg := range messages {
+		switch msg.Role {
+		case "system":
+			turn(ShareGPTSystem, msg)
+		case "user":
+			turn(ShareGPTHuman, msg)
+		case "tool":
+			turn(ShareGPTObservation, msg)
+		case "assistant":
+			if msg.Content != "" || len(msg.Images) > 0 || len(msg.ToolCalls) == 0 {
+				turn(ShareGPTModel, msg)
+			}
+			if len(msg.ToolCalls) == 0 {
+				continue
+			}
+			calls := make([]shareGPTCall, len(msg.ToolCalls))
+			for j, call := range msg.ToolCalls {
+				calls[j] = shareGPTCall{Name: call.Function.Name, Arguments: call.Function.Arguments}
+			}
+			var value interface{} = calls
+			if len(calls) == 1 {
+				value = calls[0]
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return conv, fmt.Errorf("%w: message %d: %v", utils.ErrBadTranscript, i, err)
+			}
+			conv.Conversations = append(conv.Conversations, ShareGPTTurn{From: ShareGPTFunctionCall, Value: string(data)})
+		default:
+			return conv, fmt.Errorf("%w: message %d: role %q has no ShareGPT equivalent", utils.ErrBadTranscript, i, msg.Role)
+		}
+	}
+	return conv, nil
+}
+
+// FromShareGPT converts a ShareGPT conversation to messages. Tool results are matched to tool calls in
+// order to recover the tool names. "user", "assistant" and "tool" are accepted as senders too.
+func FromShareGPT(conv ShareGPTConversation) ([]structures.Message, error) {
+	var out []structures.Message
+	images := conv.Images
+	var pending []string // Names of unanswered tool calls
+	lastModel := -1      // Index of a gpt turn that a function_call turn may extend
+
+	for i, turn := range conv.Conversations {
+		value := turn.Value
+		var msg structures.Message
+		for strings.HasPrefix(value, ImagePlaceholder) {
+			if len(images) == 0 {
+				return nil, fmt.Errorf("%w: turn %d: more image placeholders than images", utils.ErrBadTranscript, i)
+			}
+			msg.Images = append(msg.Images, images[0])
+			images = images[1:]
+			value = value[len(ImagePlaceholder):]
+		}
+		msg.Content = value
+
+		switch turn.From {
+		case ShareGPTSystem:
+			msg.Role = "system"
+		case ShareGPTHuman, "user":
+			msg.Role = "user"
+		case ShareGPTModel, "assistant":
+			msg.Role = "assistant"
+		case ShareGPTObservation, "tool":
+			msg.Role = "tool"
+			if len(pending) > 0 {
+				msg.ToolName, pending = pending[0], pending[1:]
+			}
+		case ShareGPTFunctionCall:
+			calls, err := parseShareGPTCalls(turn.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: turn %d: %v", utils.ErrBadTranscript, i, err)
+			}
+			for _, call := range calls {
+				pending = append(pending, call.Function.Name)
+			}
+			if lastModel == len(out)-1 && lastModel >= 0 {
+				out[lastModel].ToolCalls = calls
+			} else {
+				out = append(out, structures.Message{Role: "assistant", ToolCalls: calls})
+			}
+			lastModel = -1
+			continue
+		default:
+			return nil, fmt.Errorf("%w: turn %d: unknown sender %q", utils.ErrBadTranscript, i, turn.From)
+		}
+
+		lastModel = -1
+		if msg.Role == "assistant" {
+			lastModel = len(out)
+		}
+		out = append(out, msg)
+	}
+	if len(images) > 0 {
+		return nil, fmt.Errorf("%w: %d images without a placeholder", utils.ErrBadTranscript, len(images))
+	}
+	return out, nil
+}
+
+// parseShareGPTCalls decodes a function_call value holding one call or an array of calls.
+func parseShareGPTCalls(value string) ([]structures.ToolCall, error) {
+	var calls []shareGPTCall
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &calls); err != nil {
+			return nil, err
+		}
+	} else {
+		var call shareGPTCall
+		if err := json.Unmarshal([]byte(value), &call); err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+
+	out := make([]structures.ToolCall, len(calls))
+	for i, call := range calls {
+		if call.Arguments == nil {
+			call.Arguments = map[string]interface{}{}
+		}
+		out[i] = structures.ToolCall{Function: structures.ToolCallFunction{Name: call.Name, Arguments: call.Arguments}}
+	}
+	return out, nil
+}
+
+// WriteShareGPT writes each conversation as one line of a ShareGPT JSONL dataset.
+func WriteShareGPT(w io.Writer, conversations ...[]structures.Message) error {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false) // Keep <image> placeholders readable
+	for i, messages := range conversations {
+		conv, err := ToShareGPT(messages)
+		if err != nil {
+			return fmt.Errorf("conversation %d: %w", i, err)
+		}
+		if err := enc.Encode(conv); err != nil {
+			return err
+		}
+	}
+	return buf.Flush()
+}
+
+// ReadShareGPT reads every conversation of a ShareGPT JSONL dataset.
+func ReadShareGPT(r io.Reader) ([][]structures.Message, error) {
+	dec := json.NewDecoder(r)
+	var conversations [][]structures.Message
+	for i := 0; ; i++ {
+		var conv ShareGPTConversation
+		err := dec.Decode(&conv)
+		if errors.Is(err, io.EOF) {
+			return conversations, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", utils.ErrBadTranscript, i+1, err)
+		}
+		messages, err := FromShareGPT(conv)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		conversations = append(conversations, messages)
+	}
+}
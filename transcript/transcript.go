@@ -0,0 +1,53 @@
+// Package transcript converts conversations to and from the formats used by other tools: OpenAI chat JSON,
+// ShareGPT-style JSONL fine-tuning datasets and readable Markdown. Tool calls and images are preserved.
+package transcript
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/SamyRai/ollama-go/utils"
+	"net/http"
+	"strings"
+)
+
+// imageDataURL wraps a base64-encoded image in a data URL.
+func imageDataURL(image string) string {
+	return "data:" + imageMediaType(image) + ";base64," + image
+}
+
+// imageMediaType sniffs the media type of a base64-encoded image, defaulting to PNG.
+func imageMediaType(image string) string {
+	n := min(len(image), 700) // Enough for the 512 bytes DetectContentType looks at
+	head, _ := base64.StdEncoding.DecodeString(image[:n-n%4])
+	if mediaType := http.DetectContentType(head); strings.HasPrefix(mediaType, "image/") {
+		return mediaType
+	}
+	return "image/png"
+}
+
+// parseDataURL returns the base64 payload of a data URL.
+func parseDataURL(url string) (string, error) {
+	rest, isData := strings.CutPrefix(url, "data:")
+	meta, data, found := strings.Cut(rest, ",")
+	if !isData || !found || !strings.HasSuffix(meta, ";base64") {
+		return "", fmt.Errorf("%w: image %.40q is not a base64 data URL", utils.ErrBadTranscript, url)
+	}
+	return data, nil
+}
+
+// callQueue matches tool results to the calls that produced them, in call order.
+type callQueue map[string][]string
+
+func (q callQueue) push(name, id string) {
+	q[name] = append(q[name], id)
+}
+
+// pop returns the oldest unanswered call to name.
+func (q callQueue) pop(name string) (string, bool) {
+	ids := q[name]
+	if len(ids) == 0 {
+		return "", false
+	}
+	q[name] = ids[1:]
+	return ids[0], true
+}
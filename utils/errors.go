@@ -1,11 +1,84 @@
 package utils
 
-import "errors"
+import (
+    "errors"
+    "net/http"
+    "strings"
+    "time"
+)
 
 // Predefined errors for the Ollama client.
 var (
-    ErrInvalidResponse = errors.New("received an invalid response from the API")
-    ErrRequestFailed   = errors.New("API request failed")
-    ErrTimeout         = errors.New("request timed out")
-    ErrModelNotFound   = errors.New("specified model was not found")
+    ErrInvalidResponse  = errors.New("received an invalid response from the API")
+    ErrRequestFailed    = errors.New("API request failed")
+    ErrTimeout          = errors.New("request timed out")
+    ErrModelNotFound    = errors.New("specified model was not found")
+    ErrBadRequest       = errors.New("request was rejected as invalid")
+    ErrOverloaded       = errors.New("server is overloaded")
+    ErrCircuitOpen      = errors.New("circuit breaker is open: server marked unhealthy")
+    ErrAuthentication   = errors.New("authentication failed")
+    ErrInvalidOptions   = errors.New("invalid model options")
+    ErrSchemaValidation = errors.New("value does not match the JSON schema")
+    ErrMaxIterations    = errors.New("maximum number of tool-calling iterations reached")
+    ErrInvalidModelfile = errors.New("invalid Modelfile")
+    ErrDigestMismatch   = errors.New("model digest does not match the pinned digest")
+    ErrSessionNotFound  = errors.New("session not found")
+    ErrVersionConflict  = errors.New("session was modified concurrently")
+    ErrBadTranscript    = errors.New("transcript could not be converted")
+    ErrNoHealthyHosts   = errors.New("no healthy host is available")
 )
+
+// APIError describes a failed API call, including the error message returned by the server.
+type APIError struct {
+    StatusCode int           // HTTP status code of the response.
+    Status     string        // HTTP status line (e.g., "404 Not Found").
+    Message    string        // Error message from the server's {"error": "..."} body.
+    Method     string        // HTTP method of the request.
+    Endpoint   string        // API endpoint that was called (e.g., "/api/chat").
+    RequestID  string        // Request ID reported by the server or proxy, if any.
+    RetryAfter time.Duration // Delay requested by the server via Retry-After, if any.
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+    msg := "API request failed with status: " + e.Status
+    if e.Status == "" {
+        msg = "API request failed"
+    }
+    if e.Endpoint != "" {
+        msg += " (" + strings.TrimSpace(e.Method+" "+e.Endpoint) + ")"
+    }
+    if e.Message != "" {
+        msg += ": " + e.Message
+    }
+    if e.RequestID != "" {
+        msg += " [request id " + e.RequestID + "]"
+    }
+    return msg
+}
+
+// Is maps the error onto the predefined sentinels so callers can use errors.Is.
+func (e *APIError) Is(target error) bool {
+    switch target {
+    case ErrRequestFailed:
+        return true
+    case ErrModelNotFound:
+        return isModelNotFoundMessage(e.Message)
+    case ErrTimeout:
+        return e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusGatewayTimeout
+    case ErrOverloaded:
+        return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusServiceUnavailable
+    case ErrAuthentication:
+        return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+    case ErrBadRequest:
+        return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+    }
+    return false
+}
+
+// isModelNotFoundMessage recognises Ollama's "model 'x' not found" messages, which may
+// also arrive inside a successful streaming response.
+func isModelNotFoundMessage(msg string) bool {
+    msg = strings.ToLower(msg)
+    return strings.HasPrefix(msg, "model ") && strings.Contains(msg, "not found")
+}